@@ -1,103 +1,776 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"runtime"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/HanksJCTsai/goidleguard/internal/condition"
 	"github.com/HanksJCTsai/goidleguard/internal/config"
 	"github.com/HanksJCTsai/goidleguard/internal/preventidle"
+	"github.com/HanksJCTsai/goidleguard/internal/retry"
 	"github.com/HanksJCTsai/goidleguard/internal/schedule"
 	"github.com/HanksJCTsai/goidleguard/pkg/logger"
 )
 
 type Controller struct {
-	cfg        *config.APPConfig
+	cfg *config.APPConfig
+	// mu 保護 scheduler / healthStop / restarts，健康檢查可能在背景重啟 daemon
+	mu         sync.Mutex
 	scheduler  *schedule.Scheduler
 	healthStop chan struct{}
+	restarts   int
+	// stopped 在 StopDaemon 時關閉 (健康檢查重啟時不關閉)，通知 WaitPID 等背景工作結束
+	stopped chan struct{}
+	// pausedMu 保護 paused：tick 刻意不送出輸入的原因 (例如使用者離開)，健康檢查在暫停期間不重啟
+	pausedMu sync.Mutex
+	paused   string
+	// backend 提供閒置時間、輸入模擬與電源宣告，由呼叫端注入
+	backend *preventidle.Backend
+	// now 為目前時間來源，測試時可替換成固定時間
+	now func() time.Time
+	// chain 為依優先順序排列的防閒置策略，連續失敗時自動降級
+	chain *preventidle.StrategyChain
+	// held 為目前持有電源宣告的策略 (僅 inhibit 類策略使用)，nil 表示未持有
+	held *preventidle.Strategy
+	// onStrategyChange 在使用中的策略改變時被呼叫，例如更新系統匣提示
+	onStrategyChange func(name string)
+	// retry 為 GetIdleTime / SimulateActivity 失敗時的重試策略
+	retry retry.Policy
+	// events 傳遞重試用盡等事件給呼叫端
+	events chan Event
+	// timing 決定達到閒置門檻後何時送出模擬輸入，nextFire 為已排定的時間 (零值表示尚未排定)，
+	// firedFrom 為排定 nextFire 時的最後一次輸入時間，用來判斷之後是否有新的輸入
+	timing    *schedule.Timing
+	nextFire  time.Time
+	firedFrom time.Time
+	// logind 在設定啟用時，於工作時段持有 systemd-logind inhibitor lock
+	logind preventidle.PowerInhibitor
+	// tracker 記錄我們送出的模擬輸入，以區分系統閒置時間與使用者真正的閒置時間
+	tracker *preventidle.IdleTracker
+	// absent 表示使用者已超過 AbsenceTimeout 沒有輸入，防閒置暫停中
+	absent bool
+	// session 回報登入 session 是否鎖定，nil 表示不檢查；sessionState 為上一次讀到的狀態
+	session      preventidle.SessionStateSource
+	sessionState preventidle.SessionState
+	sessionErr   bool
+	// power 為電源條件 (acOnly / minBatteryPercent)，nil 表示不檢查；powerPaused 表示因電源暫停中
+	power       *condition.Power
+	powerPaused bool
+	// processes 為程序規則，nil 表示不限制；matched 為目前符合規則的程序 (顯示於狀態 log)
+	processes *condition.Processes
+	matched   *condition.Process
+	// pauseProcesses 為排除清單，pausedBy 為目前造成強制暫停的程序 (nil 表示未暫停)
+	pauseProcesses *condition.Processes
+	pausedBy       *condition.Process
+	// load 為系統負載條件，nil 表示不檢查；loadHigh 為負載是否偏高 (含遲滯)，loadValue 為最近一次的負載
+	load      *condition.Load
+	loadHigh  bool
+	loadValue float64
+	// holds 為不受工作時段限制、要求持續防閒置的來源
+	holds holds
+	// override 為暫時覆寫的檔案，nil 表示不支援覆寫
+	override *condition.Override
 }
 
-func NewController(cfg *config.APPConfig) *Controller {
-	return &Controller{
+// NewController 建立 Controller，所有防閒置操作都透過注入的 backend 與設定的策略執行
+func NewController(cfg *config.APPConfig, backend *preventidle.Backend) (*Controller, error) {
+	names := cfg.IdlePrevention.Strategies
+	dryRun := backend.Name == preventidle.DryRunBackendName
+	if dryRun && len(names) > 0 {
+		// strategies 會開啟真實的 backend，dry-run 時一律只使用 dry-run backend
+		logger.LogInfof("DryRun: ignoring strategies %v", names)
+		names = nil
+	}
+	strategies, err := preventidle.OpenStrategies(names, backend, cfg.IdlePrevention.Mode)
+	if err != nil {
+		return nil, err
+	}
+	c := &Controller{
 		cfg:        cfg,
 		scheduler:  schedule.InitialScheduler(cfg),
 		healthStop: make(chan struct{}),
+		stopped:    make(chan struct{}),
+		backend:    backend,
+		now:        time.Now,
+		chain:      preventidle.NewStrategyChain(strategies, cfg.IdlePrevention.MaxFailures),
+		retry:      retry.NewPolicy(cfg.RetryPolicy),
+		events:     make(chan Event, eventBuffer),
+		timing:     schedule.NewTiming(cfg.IdlePrevention.Timing),
+		// 第一次 tick 前尚未判斷條件，健康檢查先不重啟
+		paused: "starting",
+	}
+	c.tracker = preventidle.NewIdleTracker(backend.Idle, func() time.Time { return c.now() })
+	if l := cfg.IdlePrevention.Logind; l.Enabled && dryRun {
+		logger.LogInfo("DryRun: logind inhibitor lock disabled")
+	} else if l.Enabled && runtime.GOOS != "linux" {
+		logger.LogInfo("Logind: inhibitor lock is only supported on Linux, ignoring")
+	} else if l.Enabled {
+		why := l.Why
+		if why == "" {
+			why = "GoIdleGuard work session"
+		}
+		c.logind = preventidle.NewLogindInhibitor(l.What, why, l.Mode)
+	}
+	if cfg.IdlePrevention.Session.RespectLock && runtime.GOOS == "linux" && !dryRun {
+		c.session = preventidle.NewLogindSession()
+	}
+	if processes, err := condition.NewProcesses("", cfg.IdlePrevention.Processes); err != nil {
+		return nil, err
+	} else if processes.Enabled() {
+		if runtime.GOOS == "linux" {
+			c.processes = processes
+		} else {
+			logger.LogInfo("Process: rules are only supported on Linux, ignoring")
+		}
+	}
+	if pause, err := condition.NewProcesses("", cfg.IdlePrevention.PauseProcesses); err != nil {
+		return nil, err
+	} else if pause.Enabled() {
+		if runtime.GOOS == "linux" {
+			c.pauseProcesses = pause
+		} else {
+			logger.LogInfo("Process: pause list is only supported on Linux, ignoring")
+		}
 	}
+	if load := condition.NewLoad("", cfg.IdlePrevention.Load); load.Enabled() {
+		if runtime.GOOS == "linux" {
+			c.load = load
+		} else {
+			logger.LogInfo("Load: conditions are only supported on Linux, ignoring")
+		}
+	}
+	if power := condition.NewPower("", cfg.IdlePrevention.Power); power.Enabled() {
+		if runtime.GOOS == "linux" {
+			c.power = power
+		} else {
+			logger.LogInfo("Power: conditions are only supported on Linux, ignoring")
+		}
+	}
+	return c, nil
+}
+
+// OnStrategyChange 設定使用中策略改變時的通知函式
+func (c *Controller) OnStrategyChange(f func(name string)) {
+	c.onStrategyChange = f
+}
+
+// ActiveStrategy 回傳目前使用中的策略名稱
+func (c *Controller) ActiveStrategy() string {
+	return c.chain.Active().Name
 }
 
 func (c *Controller) StartDaemon() {
-	logger.LogInfo("StartDaemon: will wait for idle >=", c.cfg.IdlePrevention.Interval)
-	task := func() {
-		if schedule.CheckWorkTime(c.cfg, time.Now()) {
-			logger.LogInfo("StartDaemon: idle threshold met, starting prevention")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.startLocked()
+}
+
+func (c *Controller) startLocked() {
+	logger.LogInfof("StartDaemon: backend=%s, strategy=%s, will wait for idle >= %v",
+		c.backend.Name, c.ActiveStrategy(), c.cfg.IdlePrevention.Interval)
+	c.scheduler.ScheduleTask(c.tick)
+	// 啟動健康檢查
+	go c.healthCheckLoop(c.healthStop)
+}
 
-			idle, err := preventidle.GetIdleTime()
+// tick 為每次排程觸發時執行的防閒置流程
+func (c *Controller) tick() {
+	now := c.now()
+	// paused 為這次 tick 刻意不送出輸入的原因，結束時提供給健康檢查
+	paused := ""
+	defer func() { c.setPaused(paused) }()
+	if c.pausedByProcess() {
+		// 排除清單中的程序正在執行 (例如簡報、螢幕錄影)，不論工作時段都不可送出輸入
+		c.releaseInhibit()
+		c.releaseLogind()
+		c.nextFire = time.Time{}
+		paused = "pause process " + c.pausedBy.String()
+		return
+	}
+	workTime := schedule.CheckWorkTime(c.cfg, now)
+	hold := c.holdReason()
+	// 每次 tick 都要取樣，cpu 使用率才能依視窗計算
+	busy := c.loadAbove(now)
+	if hold != "" || busy || workTime && c.processRunning() {
+		strategy := c.chain.Active()
+		// hold 為使用者明確要求保持清醒 (例如包住長時間的指令)，負載偏高表示有工作正在執行 (例如編譯)，
+		// 兩者都不因使用者離開而暫停
+		absent := hold == "" && !busy && c.userAbsent()
+		if absent || c.powerPausing() {
+			// 使用者不在座位上或電源不允許，讓系統照常閒置 / 鎖定
+			c.releaseInhibit()
+			c.releaseLogind()
+			c.nextFire = time.Time{}
+			paused = "power"
+			if absent {
+				paused = "user absent"
+			}
+			return
+		}
+		c.acquireLogind()
+		if strategy.IsInhibit() {
+			c.acquireInhibit(strategy)
+			return
+		}
+		// 輸入類策略不需要電源宣告，降級後可能仍持有上一個策略的宣告
+		c.releaseInhibit()
+		if c.sessionLocked() {
+			// 絕不對已鎖定的 session 送出輸入
+			c.nextFire = time.Time{}
+			paused = "session locked"
+			return
+		}
+		var idle, realIdle time.Duration
+		err := c.withRetry("GetIdleTime", strategy, func() (err error) {
+			idle, realIdle, err = c.tracker.Idle()
+			return err
+		})
+		if err != nil {
+			return
+		}
+		logger.LogInfof("WaitForIdle: idle=%v/%v (user idle=%v%s)", idle, c.cfg.IdlePrevention.Interval, realIdle, c.activeStatus(hold))
+
+		// 螢幕保護依系統閒置時間觸發，因此是否送出輸入以系統閒置時間判斷
+		if c.shouldFire(now, idle) {
+			logger.LogInfo("StartDaemon: idle threshold met, starting prevention")
+			var cancelled *preventidle.UserActiveError
+			opts := c.simulateOptions()
+			var stopWatch func() string
+			opts.Cancel, stopWatch = c.watchSimulation()
+			err := preventidle.SimulateActivityWith(c.retried(strategy, c.guarded(strategy.Input)), c.inputMode(), opts)
+			if reason := stopWatch(); errors.Is(err, preventidle.ErrSimulationCancelled) {
+				// sequence 的 wait 步驟被中斷，不是策略失敗
+				logger.LogInfof("SimulateActivity (%s): %s, %v", strategy.Name, reason, err)
+				if reason != "daemon stopped" {
+					c.emit(Event{Kind: EventSimulationCancelled, Op: "SimulateActivity", Strategy: strategy.Name, Err: fmt.Errorf("%s: %w", reason, err)})
+				}
+				c.nextFire = time.Time{}
+				return
+			}
+			if errors.As(err, &cancelled) {
+				logger.LogInfof("Guard (%s): %v", strategy.Name, cancelled)
+				c.emit(Event{Kind: EventSimulationCancelled, Op: "SimulateActivity", Strategy: strategy.Name, Err: cancelled})
+				c.nextFire = time.Time{}
+				return
+			}
 			if err != nil {
-				logger.LogError("WaitForIdle:", err)
+				if errors.Is(err, retry.ErrStopped) {
+					return
+				}
+				var permErr *preventidle.UinputPermissionError
+				if errors.As(err, &permErr) {
+					logger.LogErrorf("Scheduled SimulateActivity (%s): no input can be simulated: %v", strategy.Name, permErr)
+				}
+				c.strategyFailed()
 				return
 			}
-			logger.LogInfo("WaitForIdle: idle=%v/%v", idle, c.cfg.IdlePrevention.Interval)
+			c.chain.Succeeded()
+			c.nextFire = time.Time{}
+		}
+	} else {
+		c.releaseInhibit()
+		c.releaseLogind()
+		if workTime {
+			logger.LogInfo("No matching process is running now")
+			paused = "no matching process"
+		} else {
+			logger.LogInfof("It's not working time now: %s", strings.ToLower(now.Weekday().String()))
+			paused = "not working time"
+		}
+		idle, realIdle, _ := c.tracker.Idle()
+		logger.LogInfof("WaitForIdle: idle=%v/%v (user idle=%v)", idle, c.cfg.IdlePrevention.Interval, realIdle)
+	}
+}
 
-			if idle >= c.cfg.IdlePrevention.Interval {
-				err := preventidle.SimulateActivity(c.cfg.IdlePrevention.Mode)
-				if err != nil {
-					logger.LogError("Scheduled SimulateActivity error:", err)
-					return
+// IdleTimes 回傳系統閒置時間 (會被模擬輸入歸零) 與使用者真正的閒置時間 (不含模擬輸入)
+func (c *Controller) IdleTimes() (system, user time.Duration, err error) {
+	return c.tracker.Idle()
+}
+
+// guarded 包裝策略的 InputInjector：記錄送出的輸入供 tracker 使用，
+// 並在設定 GuardWindow 時於每次送出前重新檢查，使用者剛有輸入就回傳 *UserActiveError 取消剩餘步驟
+func (c *Controller) guarded(inj preventidle.InputInjector) preventidle.InputInjector {
+	tracked := c.tracker.Track(inj)
+	window := c.cfg.IdlePrevention.GuardWindow
+	if window <= 0 {
+		return tracked
+	}
+	return preventidle.InputFunc(func(in preventidle.Input) error {
+		if _, user, err := c.tracker.Idle(); err == nil && user < window {
+			return &preventidle.UserActiveError{Idle: user, Input: in}
+		}
+		return tracked.SendInput(in)
+	})
+}
+
+// retried 包裝 InputInjector，讓每一次 SendInput 各自依 RetryPolicy 重試，
+// 序列中某個步驟失敗時只重送該步驟，不會重送已成功的步驟；*UserActiveError 不重試
+func (c *Controller) retried(strategy *preventidle.Strategy, inj preventidle.InputInjector) preventidle.InputInjector {
+	return preventidle.InputFunc(func(in preventidle.Input) error {
+		var cancelled *preventidle.UserActiveError
+		err := c.withRetry("SimulateActivity", strategy, func() error {
+			err := inj.SendInput(in)
+			if errors.As(err, &cancelled) {
+				// 使用者回來了，不是失敗，不重試
+				return nil
+			}
+			return err
+		})
+		if err == nil && cancelled != nil {
+			return cancelled
+		}
+		return err
+	})
+}
+
+// watchSimulation 在模擬輸入期間於背景監看：StopDaemon、session 鎖定或使用者在 GuardWindow 內有輸入時
+// 關閉回傳的 cancel 以中斷 sequence 的 wait 步驟。模擬結束後必須呼叫 stop，stop 回傳中斷原因 (未中斷時為空字串)
+func (c *Controller) watchSimulation() (cancel <-chan struct{}, stop func() string) {
+	interrupt := make(chan struct{})
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	// healthStop 只在排程停止後才會被替換，排程執行中讀取是安全的
+	healthStop := c.healthStop
+	var reason string
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(simulationWatchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-healthStop:
+				reason = "daemon stopped"
+			case <-ticker.C:
+				if reason = c.interruptReason(); reason == "" {
+					continue
 				}
 			}
+			close(interrupt)
+			return
+		}
+	}()
+	return interrupt, func() string {
+		close(done)
+		<-finished
+		return reason
+	}
+}
+
+// simulationWatchInterval 為模擬輸入期間檢查 session 鎖定與使用者輸入的間隔
+const simulationWatchInterval = 100 * time.Millisecond
+
+// interruptReason 回傳模擬輸入應中斷的原因：session 已鎖定，或設定 GuardWindow 時使用者剛有輸入。
+// 只讀取狀態不記錄變化，狀態變化留給下一次 tick 記錄
+func (c *Controller) interruptReason() string {
+	if c.session != nil {
+		if state, err := c.session.SessionState(); err == nil && state.Locked {
+			return "session locked"
+		}
+	}
+	if window := c.cfg.IdlePrevention.GuardWindow; window > 0 {
+		if _, user, err := c.tracker.Idle(); err == nil && user < window {
+			return fmt.Sprintf("user input %v ago", user.Round(time.Millisecond))
+		}
+	}
+	return ""
+}
+
+// sessionLocked 讀取登入 session 的狀態並記錄變化，回傳是否已鎖定；
+// 無法讀取時視為未鎖定 (例如沒有 logind 的環境)，錯誤只記錄一次
+func (c *Controller) sessionLocked() bool {
+	if c.session == nil {
+		return false
+	}
+	state, err := c.session.SessionState()
+	if err != nil {
+		if !c.sessionErr {
+			logger.LogErrorf("Session: state unavailable, assuming unlocked: %v", err)
+			c.sessionErr = true
+		}
+		return false
+	}
+	c.sessionErr = false
+	if state.Locked != c.sessionState.Locked {
+		if state.Locked {
+			logger.LogInfo("Session: locked, suppressing simulated input")
 		} else {
-			logger.LogInfo("It's not working time now: %s", strings.ToLower(time.Now().Weekday().String()))
-			idle, _ := preventidle.GetIdleTime()
-			logger.LogInfo("WaitForIdle: idle=%v/%v", idle, c.cfg.IdlePrevention.Interval)
+			logger.LogInfo("Session: unlocked, resuming simulated input")
 		}
 	}
+	if state.Idle != c.sessionState.Idle {
+		logger.LogInfof("Session: idle hint changed to %v", state.Idle)
+	}
+	c.sessionState = state
+	return state.Locked
+}
 
-	c.scheduler.ScheduleTask(task)
-	// 啟動健康檢查
-	go c.healthCheckLoop()
+// processRunning 依程序規則回傳是否有符合的程序正在執行，並記錄符合程序的變化；
+// 未設定程序規則時一律回傳 true，讀取 /proc 失敗時視為沒有符合的程序
+func (c *Controller) processRunning() bool {
+	if c.processes == nil {
+		return true
+	}
+	proc, err := c.processes.Match()
+	if err != nil {
+		logger.LogError("Process: list error:", err)
+	}
+	switch {
+	case proc != nil && (c.matched == nil || c.matched.PID != proc.PID):
+		logger.LogInfof("Process: %s matched (cmdline %q), idle prevention active", proc, proc.Cmdline)
+	case proc == nil && c.matched != nil:
+		logger.LogInfof("Process: %s exited and no other process matches, idle prevention inactive", c.matched)
+	}
+	c.matched = proc
+	return proc != nil
+}
+
+// pausedByProcess 回傳排除清單中是否有程序正在執行，並記錄暫停與恢復；
+// 讀取 /proc 失敗時維持原本的狀態，避免在無法確認時送出輸入
+func (c *Controller) pausedByProcess() bool {
+	if c.pauseProcesses == nil {
+		return false
+	}
+	proc, err := c.pauseProcesses.Match()
+	if err != nil {
+		logger.LogError("Process: list error:", err)
+		return c.pausedBy != nil
+	}
+	switch {
+	case proc != nil && c.pausedBy == nil:
+		logger.LogInfof("Process: %s is running (cmdline %q), pausing idle prevention", proc, proc.Cmdline)
+	case proc == nil && c.pausedBy != nil:
+		logger.LogInfof("Process: %s exited, resuming idle prevention", c.pausedBy)
+	}
+	c.pausedBy = proc
+	return proc != nil
+}
+
+// activeStatus 回傳狀態 log 中顯示的啟用原因：hold 或符合的程序，兩者皆無時為空字串
+func (c *Controller) activeStatus(hold string) string {
+	if hold != "" {
+		return ", hold=" + hold
+	}
+	if c.loadHigh {
+		return fmt.Sprintf(", %s=%.2f", c.load.Metric, c.loadValue)
+	}
+	if c.matched == nil {
+		return ""
+	}
+	return ", process=" + c.matched.String()
+}
+
+// userAbsent 依使用者真正的閒置時間更新離開狀態並記錄轉換；未設定 AbsenceTimeout 時一律回傳 false
+func (c *Controller) userAbsent() bool {
+	timeout := c.cfg.IdlePrevention.AbsenceTimeout
+	if timeout <= 0 {
+		return false
+	}
+	_, user, err := c.tracker.Idle()
+	if err != nil {
+		// 查詢失敗時維持原本的狀態
+		logger.LogError("Absence: GetIdleTime error:", err)
+		return c.absent
+	}
+	switch {
+	case !c.absent && user >= timeout:
+		c.absent = true
+		logger.LogInfof("Absence: no user input for %v (timeout %v), pausing idle prevention until the user returns", user.Round(time.Second), timeout)
+	case c.absent && user < timeout:
+		c.absent = false
+		logger.LogInfof("Absence: user returned (idle %v), resuming idle prevention", user.Round(time.Second))
+	}
+	return c.absent
+}
+
+// powerPausing 依電源條件更新暫停狀態並記錄轉換；讀取失敗時維持原本的狀態
+func (c *Controller) powerPausing() bool {
+	if c.power == nil {
+		return false
+	}
+	ok, reason, err := c.power.Check()
+	if err != nil {
+		logger.LogError("Power: status error:", err)
+		return c.powerPaused
+	}
+	switch {
+	case !ok && !c.powerPaused:
+		c.powerPaused = true
+		logger.LogInfof("Power: %s, pausing idle prevention", reason)
+	case ok && c.powerPaused:
+		c.powerPaused = false
+		logger.LogInfo("Power: conditions met again, resuming idle prevention")
+	}
+	return c.powerPaused
+}
+
+// loadAbove 取樣系統負載並依兩個門檻更新偏高狀態、記錄轉換；未設定負載條件時一律回傳 false
+func (c *Controller) loadAbove(now time.Time) bool {
+	if c.load == nil {
+		return false
+	}
+	value, err := c.load.Value(now)
+	if err != nil {
+		// 讀取失敗時維持原本的狀態
+		logger.LogError("Load: read error:", err)
+		return c.loadHigh
+	}
+	c.loadValue = value
+	high := c.load.Above(c.loadHigh, value)
+	switch {
+	case high && !c.loadHigh:
+		logger.LogInfof("Load: %s=%.2f reached %v, keeping awake regardless of schedule", c.load.Metric, value, c.load.High)
+	case !high && c.loadHigh:
+		logger.LogInfof("Load: %s=%.2f dropped below %v, returning to schedule", c.load.Metric, value, c.load.Low)
+	}
+	c.loadHigh = high
+	return high
+}
+
+// shouldFire 依 timing model 判斷這次 tick 是否要送出模擬輸入。
+// 每段閒置 (以最後一次輸入的時間區分) 只排定一次送出時間並記錄在 log 中，
+// 時間未到時要求排程在該時間額外執行 tick，實際送出時間因此不會對齊排程週期。
+func (c *Controller) shouldFire(now time.Time, idle time.Duration) bool {
+	last := now.Add(-idle)
+	if c.nextFire.IsZero() || absDuration(last.Sub(c.firedFrom)) > lastInputSlack {
+		// 尚未排定或之後有新的輸入，重新排定
+		c.firedFrom = last
+		c.nextFire = c.timing.NextFire(last, c.cfg.IdlePrevention.Interval)
+		logger.LogInfof("Timing: next activity at %s (model=%s, in %v)",
+			c.nextFire.Format("15:04:05.000"), c.timing.Model, c.nextFire.Sub(now).Round(time.Millisecond))
+	}
+	if now.Before(c.nextFire) {
+		c.scheduler.WakeAfter(c.nextFire.Sub(now))
+		return false
+	}
+	return true
+}
+
+// lastInputSlack 為不同次查詢推算出的最後輸入時間可能的誤差 (閒置時間的精確度與查詢延遲)
+const lastInputSlack = 50 * time.Millisecond
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// withRetry 依 RetryPolicy 執行 fn，每次失敗交由 preventidle.HandleError 處理，
+// 重試用盡時發出 EventRetryExhausted 事件。
+func (c *Controller) withRetry(op string, strategy *preventidle.Strategy, fn func() error) error {
+	// healthStop 只在排程停止後才會被替換，排程執行中讀取是安全的
+	err := c.retry.Do(op, c.healthStop, fn, func(attempt int, err error) {
+		preventidle.HandleError(fmt.Errorf("%s (%s) attempt %d/%d: %w", op, strategy.Name, attempt, c.retry.MaxRetries+1, err))
+	})
+	var exhausted *retry.ExhaustedError
+	if errors.As(err, &exhausted) {
+		c.emit(Event{Kind: EventRetryExhausted, Op: op, Strategy: strategy.Name, Err: exhausted})
+	}
+	return err
+}
+
+// inputMode 回傳輸入類策略使用的模擬模式；mode 為 inhibit 時 fallback 到輸入策略則使用 mixed
+func (c *Controller) inputMode() string {
+	if c.cfg.IdlePrevention.Mode == "inhibit" {
+		return "mixed"
+	}
+	return c.cfg.IdlePrevention.Mode
+}
+
+// simulateOptions 由設定組出模擬輸入的內容
+func (c *Controller) simulateOptions() preventidle.SimulateOptions {
+	key, mouse := c.cfg.IdlePrevention.Key, c.cfg.IdlePrevention.Mouse
+	var steps []preventidle.Step
+	for _, st := range c.cfg.IdlePrevention.Sequence {
+		steps = append(steps, preventidle.Step{
+			Action: st.Action,
+			Key:    preventidle.Key{Name: st.Key, Modifiers: st.Modifiers},
+			Dx:     st.DX,
+			Dy:     st.DY,
+			Amount: st.Amount,
+			Wait:   time.Duration(st.Ms) * time.Millisecond,
+		})
+	}
+	return preventidle.SimulateOptions{
+		Key:      preventidle.Key{Name: key.Name, Modifiers: key.Modifiers},
+		Mouse:    preventidle.Mouse{Pattern: mouse.Pattern, Amplitude: mouse.Amplitude},
+		Sequence: steps,
+	}
+}
+
+// strategyFailed 記錄目前策略失敗一次，連續失敗達上限時降級到下一個策略
+func (c *Controller) strategyFailed() {
+	demoted := c.chain.Failed()
+	if demoted == nil {
+		return
+	}
+	if demoted.IsInhibit() {
+		c.releaseInhibit()
+	}
+	active := c.ActiveStrategy()
+	logger.LogErrorf("Strategy: %s failed repeatedly, switching to %s", demoted.Name, active)
+	if c.onStrategyChange != nil {
+		c.onStrategyChange(active)
+	}
 }
 
 func (c *Controller) StopDaemon() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	close(c.stopped)
+	c.stopLocked()
+}
+
+func (c *Controller) stopLocked() {
 	logger.LogInfo("Stopping daemon...")
 	// 停健康檢查
 	close(c.healthStop)
 	// 停排程與持續輸入模擬
 	c.scheduler.StopScheduler()
-	// c.idleCtl.StopIdlePrevention()
+	// 排程已停止，可安全釋放電源宣告
+	c.releaseInhibit()
+	c.releaseLogind()
+}
+
+// acquireInhibit 在工作時段開始時以策略取得電源宣告，已持有時不重複取得
+func (c *Controller) acquireInhibit(strategy *preventidle.Strategy) {
+	if c.held == strategy {
+		return
+	}
+	if err := strategy.Inhibitor.Inhibit(); err != nil {
+		logger.LogErrorf("Inhibit (%s): PreventSleep error: %v", strategy.Name, err)
+		c.strategyFailed()
+		return
+	}
+	c.held = strategy
+	c.chain.Succeeded()
+	logger.LogInfof("Inhibit (%s): work session started, power assertion acquired", strategy.Name)
+}
+
+// releaseInhibit 在工作時段結束、策略降級或 daemon 停止時釋放電源宣告
+func (c *Controller) releaseInhibit() {
+	if c.held == nil {
+		return
+	}
+	if err := c.held.Inhibitor.Release(); err != nil {
+		logger.LogErrorf("Inhibit (%s): AllowIdle error: %v", c.held.Name, err)
+		return
+	}
+	logger.LogInfof("Inhibit (%s): power assertion released", c.held.Name)
+	c.held = nil
 }
 
 func (c *Controller) RestartDaemon() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.restartLocked()
+}
+
+func (c *Controller) restartLocked() {
 	logger.LogInfo("Restarting daemon...")
-	c.StopDaemon()
+	c.stopLocked()
 	// 確保資源釋放
 	time.Sleep(100 * time.Millisecond)
 	c.healthStop = make(chan struct{})
 	c.scheduler = schedule.InitialScheduler(c.cfg)
-	c.StartDaemon()
+	c.restarts++
+	c.startLocked()
+}
+
+// Restarts 回傳健康檢查或呼叫端觸發重啟的次數
+func (c *Controller) Restarts() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.restarts
 }
 
-func (c *Controller) healthCheckLoop() {
+// acquireLogind 在工作時段中取得 logind inhibitor lock；LogindInhibitor 本身會避免重複取得
+func (c *Controller) acquireLogind() {
+	if c.logind == nil {
+		return
+	}
+	if err := c.logind.Inhibit(); err != nil {
+		logger.LogError("Logind: Inhibit error:", err)
+	}
+}
+
+// releaseLogind 在工作時段結束或 daemon 停止時釋放 logind inhibitor lock
+func (c *Controller) releaseLogind() {
+	if c.logind == nil {
+		return
+	}
+	if err := c.logind.Release(); err != nil {
+		logger.LogError("Logind: Release error:", err)
+	}
+}
+
+// healthCheckLoop 定期檢查防閒置是否有效；stop 為本次啟動的停止通道，重啟後會換成新的通道
+func (c *Controller) healthCheckLoop(stop chan struct{}) {
 	ticker := time.NewTicker(c.cfg.Scheduler.Interval)
 	defer ticker.Stop()
 	for {
 		select {
-		case <-c.healthStop:
+		case <-stop:
 			logger.LogInfo("Health check stopped")
 			return
 		case <-ticker.C:
-			if schedule.CheckWorkTime(c.cfg, time.Now()) {
-				idleTime, err := preventidle.GetIdleTime()
-				if err != nil {
-					logger.LogError("HealthCheck: failed to get idle time:", err)
-					continue
-				}
-				// 如果閒置時間過長（例如 10 分鐘以上），可能代表模擬失效，嘗試重啟
-				if idleTime > c.cfg.IdlePrevention.Interval+(5*time.Minute) {
-					logger.LogError("HealthCheck: idle time too long (", idleTime, "), restarting prevention")
-					c.RestartDaemon()
-				} else {
-					logger.LogInfo("HealthCheck: idle time healthy (", idleTime, ")")
+			if c.needsRestart() {
+				c.mu.Lock()
+				select {
+				case <-stop:
+					// 等待鎖的期間 daemon 已被停止或重啟，不再重啟
+				default:
+					// 重啟會啟動新的健康檢查迴圈，本迴圈直接結束
+					c.restartLocked()
 				}
+				c.mu.Unlock()
+				return
 			}
 		}
 	}
 }
+
+// setPaused 記錄 tick 刻意不送出輸入的原因，空字串表示正常運作
+func (c *Controller) setPaused(reason string) {
+	c.pausedMu.Lock()
+	defer c.pausedMu.Unlock()
+	c.paused = reason
+}
+
+// pausedReason 回傳最近一次 tick 刻意不送出輸入的原因
+func (c *Controller) pausedReason() string {
+	c.pausedMu.Lock()
+	defer c.pausedMu.Unlock()
+	return c.paused
+}
+
+// needsRestart 執行一次健康檢查，回傳是否需要重啟防閒置流程
+func (c *Controller) needsRestart() bool {
+	// inhibit 類策略不送出輸入，閒置時間本來就會持續增加，不作為健康指標
+	if c.chain.Active().IsInhibit() {
+		return false
+	}
+	// 條件不成立 (非工作時段、沒有符合的程序) 或刻意暫停時閒置時間同樣會持續增加，重啟只會形成重啟迴圈
+	if reason := c.pausedReason(); reason != "" {
+		logger.LogInfof("HealthCheck: skipped while paused (%s)", reason)
+		return false
+	}
+	idleTime, err := c.backend.Idle.IdleTime()
+	if err != nil {
+		logger.LogError("HealthCheck: failed to get idle time:", err)
+		return false
+	}
+	// 如果閒置時間過長（例如 10 分鐘以上），可能代表模擬失效，嘗試重啟
+	if idleTime > c.cfg.IdlePrevention.Interval+(5*time.Minute) {
+		logger.LogError("HealthCheck: idle time too long (", idleTime, "), restarting prevention")
+		return true
+	}
+	logger.LogInfo("HealthCheck: idle time healthy (", idleTime, ")")
+	return false
+}
@@ -1,10 +1,17 @@
 package main
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/HanksJCTsai/goidleguard/internal/condition"
 	"github.com/HanksJCTsai/goidleguard/internal/config"
+	"github.com/HanksJCTsai/goidleguard/internal/preventidle"
+	"github.com/HanksJCTsai/goidleguard/internal/retry"
 	"github.com/HanksJCTsai/goidleguard/internal/schedule"
 )
 
@@ -54,10 +61,13 @@ func TestDaemonController_StartAndStop(t *testing.T) {
 	}
 
 	// 建立真實的 Controller 實例
-	ctrl := &Controller{
-		cfg:        cfg,
-		scheduler:  schedule.InitialScheduler(cfg),
-		healthStop: make(chan struct{}),
+	backend, err := preventidle.OpenBackend(preventidle.NativeBackend)
+	if err != nil {
+		t.Fatalf("OpenBackend failed: %v", err)
+	}
+	ctrl, err := NewController(cfg, backend)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
 	}
 
 	// 啟動 Daemon
@@ -73,3 +83,906 @@ func TestDaemonController_StartAndStop(t *testing.T) {
 
 	// 如果沒有 panic、沒有錯誤，代表啟動與停止都正常
 }
+
+// 2025-04-07 為星期一
+var (
+	mondayWorkTime = time.Date(2025, time.April, 7, 9, 0, 0, 0, time.Local)
+	mondayLunch    = time.Date(2025, time.April, 7, 12, 30, 0, 0, time.Local)
+)
+
+// newFakeController 建立使用 FakeBackend 與固定時間的 Controller
+func newFakeController(mode string, now time.Time) (*Controller, *preventidle.FakeBackend) {
+	cfg := &config.APPConfig{
+		Scheduler: config.SchedulerConfig{Interval: 10 * time.Millisecond},
+		IdlePrevention: config.IdlePreventionConfig{
+			Enabled:  true,
+			Interval: time.Second,
+			Mode:     mode,
+		},
+		WorkSchedule: config.WorkSchedule{
+			"monday": {
+				{Start: "08:00", End: "12:00"},
+				{Start: "13:00", End: "17:00"},
+			},
+		},
+	}
+	fake := preventidle.NewFakeBackend()
+	ctrl, err := NewController(cfg, fake.Backend())
+	if err != nil {
+		panic(err)
+	}
+	ctrl.now = func() time.Time { return now }
+	return ctrl, fake
+}
+
+func TestController_TickSimulatesWhenIdle(t *testing.T) {
+	ctrl, fake := newFakeController("mixed", mondayWorkTime)
+
+	fake.SetIdle(500 * time.Millisecond)
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 0 {
+		t.Fatalf("Expected no input below idle threshold, got %v", got)
+	}
+
+	fake.SetIdle(2 * time.Second)
+	ctrl.tick()
+	got := fake.Inputs()
+	if len(got) != 2 || got[0] != "key" || got[1] != "mouse" {
+		t.Errorf("Expected [key mouse] for mixed mode, got %v", got)
+	}
+}
+
+func TestController_TickSendsConfiguredKey(t *testing.T) {
+	ctrl, fake := newFakeController("key", mondayWorkTime)
+	ctrl.cfg.IdlePrevention.Key = config.KeyConfig{Name: "F15", Modifiers: []string{"ctrl"}}
+
+	fake.SetIdle(2 * time.Second)
+	ctrl.tick()
+	sent := fake.Sent()
+	if len(sent) != 1 || sent[0].Key.String() != "ctrl+f15" {
+		t.Errorf("Expected ctrl+f15 key press, got %+v", sent)
+	}
+}
+
+func TestController_TickSendsConfiguredMousePattern(t *testing.T) {
+	ctrl, fake := newFakeController("mouse", mondayWorkTime)
+	ctrl.cfg.IdlePrevention.Mouse = config.MouseConfig{Pattern: "circle", Amplitude: 5}
+
+	fake.SetIdle(2 * time.Second)
+	ctrl.tick()
+	sent := fake.Sent()
+	if len(sent) != 1 || sent[0].Mouse.String() != "circle(5)" {
+		t.Errorf("Expected circle(5) mouse pattern, got %+v", sent)
+	}
+}
+
+func TestController_TickScrollMode(t *testing.T) {
+	ctrl, fake := newFakeController("scroll", mondayWorkTime)
+
+	fake.SetIdle(2 * time.Second)
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 1 || got[0] != "scroll" {
+		t.Errorf("Expected a single scroll input, got %v", got)
+	}
+}
+
+func TestController_TickRunsSequence(t *testing.T) {
+	ctrl, fake := newFakeController("sequence", mondayWorkTime)
+	ctrl.cfg.IdlePrevention.Sequence = []config.SequenceStep{
+		{Action: "key", Key: "f15"},
+		{Action: "wait", Ms: 1},
+		{Action: "move", DX: 3, DY: -2},
+		{Action: "scroll", Amount: -1},
+	}
+
+	fake.SetIdle(2 * time.Second)
+	ctrl.tick()
+	sent := fake.Sent()
+	if len(sent) != 3 {
+		t.Fatalf("Expected 3 inputs, got %+v", sent)
+	}
+	if sent[0].Type != "key" || sent[0].Key.Name != "f15" {
+		t.Errorf("Expected f15 key press first, got %+v", sent[0])
+	}
+	if sent[1].Type != "move" || sent[1].Dx != 3 || sent[1].Dy != -2 {
+		t.Errorf("Expected move (3,-2) second, got %+v", sent[1])
+	}
+	if sent[2].Type != "wheel" || sent[2].Wheel != -1 {
+		t.Errorf("Expected wheel -1 third, got %+v", sent[2])
+	}
+}
+
+func TestController_SequenceRetriesOnlyFailedStep(t *testing.T) {
+	ctrl, fake := newFakeController("sequence", mondayWorkTime)
+	ctrl.retry = retry.Policy{MaxRetries: 2, Interval: time.Millisecond}
+	ctrl.cfg.IdlePrevention.Sequence = []config.SequenceStep{
+		{Action: "key", Key: "f15"},
+		{Action: "move", DX: 3, DY: -2},
+		{Action: "scroll", Amount: -1},
+	}
+	// 第二個步驟第一次送出時失敗
+	calls := 0
+	ctrl.chain = preventidle.NewStrategyChain([]*preventidle.Strategy{{
+		Name: "flaky",
+		Input: preventidle.InputFunc(func(in preventidle.Input) error {
+			calls++
+			if calls == 2 {
+				return errors.New("input blocked")
+			}
+			return fake.SendInput(in)
+		}),
+	}}, 2)
+
+	fake.SetIdle(2 * time.Second)
+	ctrl.tick()
+	sent := fake.Sent()
+	if len(sent) != 3 || sent[0].Type != "key" || sent[1].Type != "move" || sent[2].Type != "wheel" {
+		t.Fatalf("Expected each step to be sent exactly once, got %+v", sent)
+	}
+	if got := ctrl.ActiveStrategy(); got != "flaky" {
+		t.Errorf("Expected a recovered step not to count as a strategy failure, got %s", got)
+	}
+}
+
+// tickUntilInterrupted 在背景執行一次 tick，第一個輸入送出後 (sequence 進入 wait 步驟) 呼叫 interrupt，
+// 並確認 tick 在 wait 結束前就返回
+func tickUntilInterrupted(t *testing.T, ctrl *Controller, fake *preventidle.FakeBackend, interrupt func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ctrl.tick()
+	}()
+	deadline := time.After(time.Second)
+	for len(fake.Inputs()) == 0 {
+		select {
+		case <-deadline:
+			t.Fatal("Expected the first step to be sent")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	interrupt()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the wait step to be interrupted")
+	}
+	if got := fake.Inputs(); len(got) != 1 {
+		t.Errorf("Expected the remaining steps to be cancelled, got %v", got)
+	}
+}
+
+func TestController_SequenceWaitInterruptedByStop(t *testing.T) {
+	ctrl, fake := newFakeController("sequence", mondayWorkTime)
+	ctrl.cfg.IdlePrevention.Sequence = []config.SequenceStep{
+		{Action: "key", Key: "f15"},
+		{Action: "wait", Ms: 10000},
+		{Action: "key", Key: "f15"},
+	}
+	fake.SetIdle(2 * time.Second)
+	tickUntilInterrupted(t, ctrl, fake, func() { close(ctrl.healthStop) })
+}
+
+// lockingSession 回傳可在測試中途改變的 session 狀態
+type lockingSession struct {
+	mu     sync.Mutex
+	locked bool
+}
+
+func (s *lockingSession) SessionState() (preventidle.SessionState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return preventidle.SessionState{Locked: s.locked}, nil
+}
+
+func TestController_SequenceWaitInterruptedByLock(t *testing.T) {
+	ctrl, fake := newFakeController("sequence", mondayWorkTime)
+	session := &lockingSession{}
+	ctrl.session = session
+	ctrl.cfg.IdlePrevention.Sequence = []config.SequenceStep{
+		{Action: "key", Key: "f15"},
+		{Action: "wait", Ms: 10000},
+		{Action: "key", Key: "f15"},
+	}
+	fake.SetIdle(2 * time.Second)
+	tickUntilInterrupted(t, ctrl, fake, func() {
+		session.mu.Lock()
+		session.locked = true
+		session.mu.Unlock()
+	})
+	select {
+	case ev := <-ctrl.Events():
+		if ev.Kind != EventSimulationCancelled || !errors.Is(ev.Err, preventidle.ErrSimulationCancelled) {
+			t.Errorf("Unexpected event: %+v", ev)
+		}
+	default:
+		t.Fatal("Expected a simulation-cancelled event")
+	}
+	if got := ctrl.ActiveStrategy(); got != preventidle.FakeBackendName {
+		t.Errorf("Expected the interruption not to demote the strategy, got %s", got)
+	}
+}
+
+func TestController_TimingDelaysActivity(t *testing.T) {
+	ctrl, fake := newFakeController("key", mondayWorkTime)
+	ctrl.timing = schedule.NewTiming(config.TimingConfig{MinGap: 10 * time.Second})
+
+	// 閒置 2 秒：最後一次輸入在 8:59:58，最小間隔 10 秒，應排定在 9:00:08
+	fake.SetIdle(2 * time.Second)
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 0 {
+		t.Fatalf("Expected no input before the scheduled time, got %v", got)
+	}
+	if want := mondayWorkTime.Add(8 * time.Second); !ctrl.nextFire.Equal(want) {
+		t.Errorf("Expected next fire at %v, got %v", want, ctrl.nextFire)
+	}
+
+	ctrl.now = func() time.Time { return mondayWorkTime.Add(8 * time.Second) }
+	fake.SetIdle(10 * time.Second)
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 1 {
+		t.Fatalf("Expected input at the scheduled time, got %v", got)
+	}
+	if !ctrl.nextFire.IsZero() {
+		t.Errorf("Expected next fire to be cleared after simulating, got %v", ctrl.nextFire)
+	}
+}
+
+func TestController_TickOutsideWorkTime(t *testing.T) {
+	ctrl, fake := newFakeController("key", mondayLunch)
+
+	fake.SetIdle(time.Hour)
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 0 {
+		t.Errorf("Expected no input outside work time, got %v", got)
+	}
+}
+
+func TestController_TickIdleError(t *testing.T) {
+	ctrl, fake := newFakeController("key", mondayWorkTime)
+
+	fake.SetIdleError(errors.New("idle query failed"))
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 0 {
+		t.Errorf("Expected no input when idle query fails, got %v", got)
+	}
+}
+
+func TestController_RetryExhaustedEvent(t *testing.T) {
+	ctrl, fake := newFakeController("key", mondayWorkTime)
+	ctrl.retry = retry.Policy{MaxRetries: 2, Interval: time.Millisecond}
+
+	fake.SetIdleError(errors.New("idle query failed"))
+	ctrl.tick()
+
+	select {
+	case ev := <-ctrl.Events():
+		var exhausted *retry.ExhaustedError
+		if ev.Kind != EventRetryExhausted || ev.Op != "GetIdleTime" || !errors.As(ev.Err, &exhausted) || exhausted.Attempts != 3 {
+			t.Errorf("Unexpected event: %+v", ev)
+		}
+	default:
+		t.Fatal("Expected a retry-exhausted event")
+	}
+
+	// 模擬輸入失敗同樣會在重試用盡後發出事件
+	fake.SetIdleError(nil)
+	fake.SetIdle(2 * time.Second)
+	fake.SetInputError(errors.New("input blocked"))
+	ctrl.tick()
+	select {
+	case ev := <-ctrl.Events():
+		if ev.Kind != EventRetryExhausted || ev.Op != "SimulateActivity" || ev.Strategy != preventidle.FakeBackendName {
+			t.Errorf("Unexpected event: %+v", ev)
+		}
+	default:
+		t.Fatal("Expected a retry-exhausted event for SimulateActivity")
+	}
+}
+
+func TestController_InhibitMode(t *testing.T) {
+	ctrl, fake := newFakeController("inhibit", mondayWorkTime)
+	fake.SetIdle(time.Hour)
+
+	ctrl.tick()
+	ctrl.tick()
+	if held, inhibits, _ := fake.Inhibited(); !held || inhibits != 1 {
+		t.Fatalf("Expected power assertion to be acquired once, held=%v inhibits=%d", held, inhibits)
+	}
+	if got := fake.Inputs(); len(got) != 0 {
+		t.Errorf("Expected no synthetic input in inhibit mode, got %v", got)
+	}
+
+	// 工作時段結束時釋放
+	ctrl.now = func() time.Time { return mondayLunch }
+	ctrl.tick()
+	if held, _, releases := fake.Inhibited(); held || releases != 1 {
+		t.Errorf("Expected power assertion to be released after work session, held=%v releases=%d", held, releases)
+	}
+}
+
+func TestController_StopDaemonReleasesInhibit(t *testing.T) {
+	ctrl, fake := newFakeController("inhibit", mondayWorkTime)
+
+	ctrl.StartDaemon()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if held, _, _ := fake.Inhibited(); held {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	ctrl.StopDaemon()
+
+	if held, inhibits, releases := fake.Inhibited(); held || inhibits != 1 || releases != 1 {
+		t.Errorf("Expected one inhibit and one release, held=%v inhibits=%d releases=%d", held, inhibits, releases)
+	}
+}
+
+func TestController_StartDaemonSimulatesActivity(t *testing.T) {
+	ctrl, fake := newFakeController("mouse", mondayWorkTime)
+	fake.SetIdle(2 * time.Second)
+
+	ctrl.StartDaemon()
+	defer ctrl.StopDaemon()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if len(fake.Inputs()) > 0 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("Expected StartDaemon to simulate activity through the backend")
+}
+
+func TestController_NeedsRestart(t *testing.T) {
+	ctrl, fake := newFakeController("key", mondayWorkTime)
+
+	fake.SetIdle(10 * time.Minute)
+	if ctrl.needsRestart() {
+		t.Error("Expected no restart before the first tick")
+	}
+
+	// 模擬輸入失敗，閒置時間無法歸零
+	fake.SetInputError(errors.New("input blocked"))
+	fake.SetIdle(2 * time.Second)
+	ctrl.tick()
+	if ctrl.needsRestart() {
+		t.Error("Expected healthy idle time not to require restart")
+	}
+
+	fake.SetIdle(10 * time.Minute)
+	ctrl.tick()
+	if !ctrl.needsRestart() {
+		t.Error("Expected idle time beyond interval+5m to require restart")
+	}
+
+	ctrl.now = func() time.Time { return mondayLunch }
+	ctrl.tick()
+	if ctrl.needsRestart() {
+		t.Error("Expected no restart outside work time")
+	}
+
+	inhibit, fake2 := newFakeController("inhibit", mondayWorkTime)
+	fake2.SetIdle(time.Hour)
+	if inhibit.needsRestart() {
+		t.Error("Expected inhibit mode never to require restart")
+	}
+}
+
+func TestController_HealthCheckLoopRestarts(t *testing.T) {
+	ctrl, fake := newFakeController("key", mondayWorkTime)
+	// 讓模擬輸入失敗，閒置時間持續過長，健康檢查應重啟排程
+	fake.SetInputError(errors.New("input blocked"))
+	fake.SetIdle(10 * time.Minute)
+
+	ctrl.StartDaemon()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) && ctrl.Restarts() == 0 {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if ctrl.Restarts() == 0 {
+		t.Fatal("Expected health check to restart the daemon")
+	}
+	// 恢復正常後停止，避免持續重啟
+	fake.SetIdle(0)
+	time.Sleep(50 * time.Millisecond)
+	ctrl.StopDaemon()
+}
+
+func TestController_HealthCheckSkipsWhileAbsent(t *testing.T) {
+	ctrl, fake := newFakeController("key", mondayWorkTime)
+	ctrl.cfg.IdlePrevention.AbsenceTimeout = time.Second
+	// 使用者離開：閒置時間遠超過 interval+5m，但這是刻意暫停，不應重啟
+	fake.SetIdle(time.Hour)
+	ctrl.tick()
+	if !ctrl.absent {
+		t.Fatal("Expected the user to be marked absent")
+	}
+
+	ctrl.StartDaemon()
+	// 經過多個健康檢查週期 (Scheduler.Interval = 10ms)
+	time.Sleep(100 * time.Millisecond)
+	ctrl.StopDaemon()
+	if got := ctrl.Restarts(); got != 0 {
+		t.Errorf("Expected no restarts while the user is absent, got %d", got)
+	}
+	if got := fake.Inputs(); len(got) != 0 {
+		t.Errorf("Expected no input while the user is absent, got %v", got)
+	}
+}
+
+func TestController_StrategyFallback(t *testing.T) {
+	ctrl, primary := newFakeController("key", mondayWorkTime)
+	secondary := preventidle.NewFakeBackend()
+	ctrl.chain = preventidle.NewStrategyChain([]*preventidle.Strategy{
+		{Name: "inhibit", Inhibitor: primary},
+		{Name: "secondary", Input: secondary},
+	}, 2)
+	var changes []string
+	ctrl.OnStrategyChange(func(name string) { changes = append(changes, name) })
+
+	primary.SetInhibitError(errors.New("assertion refused"))
+	primary.SetIdle(2 * time.Second)
+	ctrl.tick()
+	if got := ctrl.ActiveStrategy(); got != "inhibit" {
+		t.Fatalf("Expected inhibit to stay active after one failure, got %s", got)
+	}
+	ctrl.tick()
+	if got := ctrl.ActiveStrategy(); got != "secondary" {
+		t.Fatalf("Expected demotion to secondary after two failures, got %s", got)
+	}
+	if len(changes) != 1 || changes[0] != "secondary" {
+		t.Errorf("Expected one strategy change notification, got %v", changes)
+	}
+
+	ctrl.tick()
+	if got := secondary.Inputs(); len(got) != 1 || got[0] != "key" {
+		t.Errorf("Expected secondary strategy to simulate a key press, got %v", got)
+	}
+}
+
+func TestController_StrategyReleasesOnDemotion(t *testing.T) {
+	ctrl, primary := newFakeController("key", mondayWorkTime)
+	secondary := preventidle.NewFakeBackend()
+	ctrl.chain = preventidle.NewStrategyChain([]*preventidle.Strategy{
+		{Name: "secondary", Input: secondary},
+		{Name: "inhibit", Inhibitor: primary},
+	}, 1)
+
+	secondary.SetInputError(errors.New("input blocked"))
+	primary.SetIdle(2 * time.Second)
+	ctrl.tick() // secondary 失敗，降級到 inhibit
+	ctrl.tick() // 取得電源宣告
+	if held, _, _ := primary.Inhibited(); !held {
+		t.Fatal("Expected inhibit strategy to acquire the power assertion")
+	}
+
+	// inhibit 策略被降級時應釋放已持有的電源宣告
+	ctrl.strategyFailed()
+	if held, _, releases := primary.Inhibited(); held || releases != 1 {
+		t.Errorf("Expected power assertion to be released after demotion, held=%v releases=%d", held, releases)
+	}
+	if got := ctrl.ActiveStrategy(); got != "secondary" {
+		t.Errorf("Expected chain to wrap around to secondary, got %s", got)
+	}
+}
+
+func TestController_DryRun(t *testing.T) {
+	cfg := &config.APPConfig{
+		Scheduler: config.SchedulerConfig{Interval: 10 * time.Millisecond},
+		IdlePrevention: config.IdlePreventionConfig{
+			Enabled:    true,
+			Interval:   time.Second,
+			Mode:       "key",
+			Strategies: []string{"uinput", "x11"},
+			Logind:     config.LogindConfig{Enabled: true},
+		},
+		WorkSchedule: config.WorkSchedule{
+			"monday": {{Start: "08:00", End: "17:00"}},
+		},
+	}
+	dry := preventidle.NewDryRunBackend()
+	ctrl, err := NewController(cfg, dry.Backend())
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	// dry-run 不可開啟真實的策略或 logind lock
+	if got := ctrl.ActiveStrategy(); got != preventidle.DryRunBackendName {
+		t.Errorf("Expected dry-run strategy, got %s", got)
+	}
+	if ctrl.logind != nil {
+		t.Error("Expected logind inhibitor to be disabled in dry-run")
+	}
+
+	now := mondayWorkTime
+	ctrl.now = func() time.Time { return now }
+	dry.SetClock(func() time.Time { return now })
+
+	ctrl.tick()
+	if got := dry.Records(); len(got) != 0 {
+		t.Fatalf("Expected nothing recorded below idle threshold, got %v", got)
+	}
+
+	now = now.Add(2 * time.Second)
+	ctrl.tick()
+	got := dry.Records()
+	if len(got) != 1 || got[0].Action != "key shift" {
+		t.Errorf("Expected a recorded key press, got %v", got)
+	}
+}
+
+func TestController_UserIdleExcludesSimulatedInput(t *testing.T) {
+	now := mondayWorkTime
+	ctrl, fake := newFakeController("key", now)
+	ctrl.now = func() time.Time { return now }
+
+	fake.SetIdle(2 * time.Second)
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 1 {
+		t.Fatalf("Expected one simulated input, got %v", got)
+	}
+
+	// 模擬輸入之後系統閒置時間從 0 開始，使用者閒置時間仍從上一次真正的輸入起算
+	now = now.Add(time.Minute)
+	fake.SetIdle(time.Minute)
+	system, user, err := ctrl.IdleTimes()
+	if err != nil {
+		t.Fatalf("IdleTimes failed: %v", err)
+	}
+	if system != time.Minute || user != time.Minute+2*time.Second {
+		t.Errorf("Expected 1m system / 1m2s user idle, got %v/%v", system, user)
+	}
+}
+
+func TestController_AbsenceTimeout(t *testing.T) {
+	now := mondayWorkTime
+	ctrl, fake := newFakeController("key", now)
+	ctrl.now = func() time.Time { return now }
+	ctrl.cfg.IdlePrevention.AbsenceTimeout = time.Minute
+
+	// 使用者閒置 2 秒：照常模擬輸入
+	fake.SetIdle(2 * time.Second)
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 1 {
+		t.Fatalf("Expected one simulated input, got %v", got)
+	}
+
+	// 之後只有模擬輸入，使用者真正的閒置時間超過 1 分鐘：暫停
+	now = now.Add(time.Minute)
+	fake.SetIdle(time.Minute)
+	ctrl.tick()
+	if !ctrl.absent {
+		t.Fatal("Expected controller to detect user absence")
+	}
+	if got := fake.Inputs(); len(got) != 1 {
+		t.Errorf("Expected no input while the user is absent, got %v", got)
+	}
+
+	// 使用者回來後恢復
+	now = now.Add(time.Minute)
+	fake.SetIdle(2 * time.Second)
+	ctrl.tick()
+	if ctrl.absent {
+		t.Error("Expected controller to resume after the user returned")
+	}
+	if got := fake.Inputs(); len(got) != 2 {
+		t.Errorf("Expected prevention to resume, got %v", got)
+	}
+}
+
+func TestController_AbsenceReleasesInhibit(t *testing.T) {
+	now := mondayWorkTime
+	ctrl, fake := newFakeController("inhibit", now)
+	ctrl.now = func() time.Time { return now }
+	ctrl.cfg.IdlePrevention.AbsenceTimeout = time.Minute
+
+	ctrl.tick()
+	if held, _, _ := fake.Inhibited(); !held {
+		t.Fatal("Expected power assertion to be held while the user is present")
+	}
+
+	now = now.Add(2 * time.Minute)
+	fake.SetIdle(2 * time.Minute)
+	ctrl.tick()
+	if held, _, _ := fake.Inhibited(); held {
+		t.Error("Expected power assertion to be released while the user is absent")
+	}
+}
+
+func TestController_GuardCancelsWhenUserActive(t *testing.T) {
+	ctrl, fake := newFakeController("sequence", mondayWorkTime)
+	ctrl.cfg.IdlePrevention.GuardWindow = time.Second
+	ctrl.cfg.IdlePrevention.Sequence = []config.SequenceStep{
+		{Action: "key", Key: "f15"},
+		{Action: "key", Key: "f15"},
+	}
+	// 第一次查詢時使用者已閒置 2 秒，送出前再次檢查時使用者剛好開始打字
+	calls := 0
+	ctrl.tracker = preventidle.NewIdleTracker(preventidle.IdleFunc(func() (time.Duration, error) {
+		calls++
+		if calls == 1 {
+			return 2 * time.Second, nil
+		}
+		return 0, nil
+	}), ctrl.now)
+
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 0 {
+		t.Errorf("Expected all steps to be cancelled, got %v", got)
+	}
+	if calls != 2 {
+		t.Errorf("Expected the cancelled simulation not to be retried, got %d idle queries", calls)
+	}
+	select {
+	case ev := <-ctrl.Events():
+		var active *preventidle.UserActiveError
+		if ev.Kind != EventSimulationCancelled || !errors.As(ev.Err, &active) {
+			t.Errorf("Unexpected event: %+v", ev)
+		}
+	default:
+		t.Fatal("Expected a simulation-cancelled event")
+	}
+	if got := ctrl.ActiveStrategy(); got != preventidle.FakeBackendName {
+		t.Errorf("Expected cancellation not to demote the strategy, got %s", got)
+	}
+}
+
+// fakeSession 回傳固定的 session 狀態
+type fakeSession struct {
+	state preventidle.SessionState
+	err   error
+}
+
+func (s *fakeSession) SessionState() (preventidle.SessionState, error) {
+	return s.state, s.err
+}
+
+func TestController_SuppressesInputWhileLocked(t *testing.T) {
+	ctrl, fake := newFakeController("key", mondayWorkTime)
+	session := &fakeSession{state: preventidle.SessionState{Locked: true}}
+	ctrl.session = session
+
+	fake.SetIdle(2 * time.Second)
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 0 {
+		t.Fatalf("Expected no input into a locked session, got %v", got)
+	}
+	// 鎖定期間閒置時間持續增加，健康檢查不應重啟
+	fake.SetIdle(time.Hour)
+	ctrl.tick()
+	if ctrl.needsRestart() {
+		t.Error("Expected no restart while the session is locked")
+	}
+
+	// 解鎖後自動恢復
+	session.state.Locked = false
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 1 {
+		t.Errorf("Expected input to resume after unlock, got %v", got)
+	}
+
+	// 無法讀取狀態時視為未鎖定
+	session.err = errors.New("no logind")
+	fake.SetIdle(2 * time.Second)
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 2 {
+		t.Errorf("Expected input when session state is unavailable, got %v", got)
+	}
+}
+
+// writeFiles 依相對路徑在 root 下建立檔案
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+}
+
+func TestController_PowerPausesPrevention(t *testing.T) {
+	ctrl, fake := newFakeController("key", mondayWorkTime)
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"AC/type":       "Mains",
+		"AC/online":     "0",
+		"BAT0/type":     "Battery",
+		"BAT0/capacity": "80",
+	})
+	ctrl.power = condition.NewPower(root, config.PowerConfig{ACOnly: true})
+
+	fake.SetIdle(2 * time.Second)
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 0 || !ctrl.powerPaused {
+		t.Fatalf("Expected prevention to pause on battery, got %v", got)
+	}
+	// 暫停期間閒置時間持續增加，健康檢查不應重啟
+	fake.SetIdle(time.Hour)
+	ctrl.tick()
+	if ctrl.needsRestart() {
+		t.Error("Expected no restart while paused on battery")
+	}
+
+	// 接上電源後恢復
+	writeFiles(t, root, map[string]string{"AC/online": "1"})
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 1 || ctrl.powerPaused {
+		t.Errorf("Expected prevention to resume on AC, got %v", got)
+	}
+}
+
+func TestController_ProcessRules(t *testing.T) {
+	ctrl, fake := newFakeController("key", mondayWorkTime)
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"1/comm": "systemd"})
+	processes, err := condition.NewProcesses(root, []config.ProcessRule{{Name: "^zoom$"}})
+	if err != nil {
+		t.Fatalf("NewProcesses failed: %v", err)
+	}
+	ctrl.processes = processes
+
+	fake.SetIdle(2 * time.Second)
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 0 {
+		t.Fatalf("Expected no input without a matching process, got %v", got)
+	}
+
+	// 沒有符合的程序時刻意不防閒置，健康檢查不應重啟
+	fake.SetIdle(time.Hour)
+	ctrl.tick()
+	if ctrl.needsRestart() {
+		t.Error("Expected no restart without a matching process")
+	}
+
+	writeFiles(t, root, map[string]string{"4242/comm": "zoom"})
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 1 {
+		t.Fatalf("Expected input while zoom is running, got %v", got)
+	}
+	if got := ctrl.activeStatus(""); got != ", process=zoom[4242]" {
+		t.Errorf("Expected matched process in the status, got %q", got)
+	}
+
+	// 工作時段外即使程序在執行也不防閒置
+	ctrl.now = func() time.Time { return mondayLunch }
+	fake.SetIdle(2 * time.Second)
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 1 {
+		t.Errorf("Expected no input outside work time, got %v", got)
+	}
+}
+
+func TestController_PauseProcesses(t *testing.T) {
+	ctrl, fake := newFakeController("inhibit", mondayWorkTime)
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"1/comm": "systemd"})
+	pause, err := condition.NewProcesses(root, []config.ProcessRule{{Name: "^obs$"}})
+	if err != nil {
+		t.Fatalf("NewProcesses failed: %v", err)
+	}
+	ctrl.pauseProcesses = pause
+
+	ctrl.tick()
+	if held, _, _ := fake.Inhibited(); !held {
+		t.Fatal("Expected power assertion to be held")
+	}
+
+	// 錄影程序啟動：立即暫停並釋放宣告
+	writeFiles(t, root, map[string]string{"777/comm": "obs"})
+	ctrl.tick()
+	if held, _, _ := fake.Inhibited(); held || ctrl.pausedBy == nil || ctrl.pausedBy.PID != 777 {
+		t.Fatalf("Expected prevention to be paused by obs[777], held=%v pausedBy=%v", held, ctrl.pausedBy)
+	}
+
+	// 程序結束後恢復
+	if err := os.RemoveAll(filepath.Join(root, "777")); err != nil {
+		t.Fatal(err)
+	}
+	ctrl.tick()
+	if held, _, _ := fake.Inhibited(); !held || ctrl.pausedBy != nil {
+		t.Errorf("Expected prevention to resume after obs exited, held=%v", held)
+	}
+}
+
+func TestController_HealthCheckSkipsWhilePausedByProcess(t *testing.T) {
+	ctrl, fake := newFakeController("key", mondayWorkTime)
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"777/comm": "obs"})
+	pause, err := condition.NewProcesses(root, []config.ProcessRule{{Name: "^obs$"}})
+	if err != nil {
+		t.Fatalf("NewProcesses failed: %v", err)
+	}
+	ctrl.pauseProcesses = pause
+
+	// 錄影期間不送出輸入，閒置時間持續增加也不應重啟
+	fake.SetIdle(time.Hour)
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 0 {
+		t.Fatalf("Expected no input while obs is running, got %v", got)
+	}
+	if ctrl.needsRestart() {
+		t.Error("Expected no restart while paused by obs")
+	}
+}
+
+func TestController_LoadOutsideWorkTime(t *testing.T) {
+	ctrl, fake := newFakeController("key", mondayLunch)
+	ctrl.cfg.IdlePrevention.AbsenceTimeout = time.Second
+	root := t.TempDir()
+	ctrl.load = condition.NewLoad(root, config.SystemLoadConfig{High: 4, Low: 2})
+
+	steps := []struct {
+		loadavg string
+		inputs  int
+	}{
+		{"1.00 1.00 1.00 1/100 1", 0},
+		{"4.50 2.00 1.00 9/100 1", 1}, // 編譯開始，即使使用者不在也持續防閒置
+		{"3.00 2.50 1.50 5/100 1", 2}, // 介於兩個門檻之間，不會立即解除
+		{"1.50 2.50 1.50 1/100 1", 2},
+	}
+	for i, s := range steps {
+		writeFiles(t, root, map[string]string{"loadavg": s.loadavg})
+		fake.SetIdle(2 * time.Second)
+		ctrl.tick()
+		if got := fake.Inputs(); len(got) != s.inputs {
+			t.Fatalf("Step %d (loadavg %s): expected %d inputs, got %v", i, s.loadavg, s.inputs, got)
+		}
+		if i == 1 {
+			if got := ctrl.activeStatus(""); got != ", load1=4.50" {
+				t.Errorf("Expected load in the status, got %q", got)
+			}
+		}
+	}
+}
+
+func TestController_FiresAtScheduledTimeNotTickGrid(t *testing.T) {
+	const tick = 100 * time.Millisecond
+	cfg := &config.APPConfig{
+		Scheduler: config.SchedulerConfig{Interval: tick},
+		IdlePrevention: config.IdlePreventionConfig{
+			Enabled:  true,
+			Interval: 130 * time.Millisecond,
+			Mode:     "key",
+		},
+	}
+	dry := preventidle.NewDryRunBackend()
+	ctrl, err := NewController(cfg, dry.Backend())
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	release := ctrl.Hold("test")
+	defer release()
+
+	// 以現在作為最後一次輸入
+	start := time.Now()
+	dry.SetClock(time.Now)
+	ctrl.StartDaemon()
+	time.Sleep(5*tick + tick/2)
+	ctrl.StopDaemon()
+
+	var fires []time.Time
+	for _, r := range dry.Records() {
+		if r.Action == "key shift" {
+			fires = append(fires, r.Time)
+		}
+	}
+	if len(fires) < 3 {
+		t.Fatalf("Expected at least 3 simulated inputs, got %v", dry.Records())
+	}
+	// 只在週期 tick 送出時，第一次在 200ms、之後每 200ms；依排定時間送出則約每 130ms
+	prev := start
+	for i, at := range fires {
+		gap := at.Sub(prev)
+		if gap < 130*time.Millisecond || gap > 170*time.Millisecond {
+			t.Errorf("Input %d: expected about 130ms after the previous input, got %v (tick grid is %v)", i, gap, tick)
+		}
+		prev = at
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"time"
+
+	"github.com/HanksJCTsai/goidleguard/pkg/logger"
+)
+
+// EventKind 區分 Controller 發出的事件種類
+type EventKind int
+
+const (
+	// EventRetryExhausted 表示 GetIdleTime 或 SimulateActivity 依 RetryPolicy 重試後仍然失敗
+	EventRetryExhausted EventKind = iota
+	// EventSimulationCancelled 表示送出模擬輸入前偵測到使用者正在操作，剩餘的步驟已取消
+	EventSimulationCancelled
+)
+
+// Event 為 Controller 對外發出的事件，由 Events() 取得
+type Event struct {
+	Kind     EventKind
+	Time     time.Time
+	Op       string // 失敗或取消的操作，例如 "GetIdleTime"
+	Strategy string // 發生事件時使用中的策略
+	Err      error
+}
+
+func (k EventKind) String() string {
+	switch k {
+	case EventRetryExhausted:
+		return "retry-exhausted"
+	case EventSimulationCancelled:
+		return "simulation-cancelled"
+	default:
+		return "unknown"
+	}
+}
+
+// eventBuffer 為事件通道的容量，通道滿時丟棄新事件以免阻塞排程
+const eventBuffer = 16
+
+// Events 回傳 Controller 的事件通道
+func (c *Controller) Events() <-chan Event {
+	return c.events
+}
+
+// emit 發出事件，沒有人讀取且通道已滿時丟棄
+func (c *Controller) emit(ev Event) {
+	ev.Time = c.now()
+	select {
+	case c.events <- ev:
+	default:
+		logger.LogDebugf("Event dropped (buffer full): %s %s: %v", ev.Kind, ev.Op, ev.Err)
+	}
+}
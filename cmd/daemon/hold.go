@@ -0,0 +1,55 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/HanksJCTsai/goidleguard/pkg/logger"
+)
+
+// holds 記錄目前要求持續防閒置的來源 (例如 run 包住的指令)，
+// 有任何 hold 時不受工作時段、程序規則與 absence timeout 限制
+type holds struct {
+	mu     sync.Mutex
+	next   int
+	active map[int]string
+}
+
+// Hold 開始一個以 reason 命名的 hold，回傳的 release 結束它 (重複呼叫無作用)
+func (c *Controller) Hold(reason string) (release func()) {
+	c.holds.mu.Lock()
+	if c.holds.active == nil {
+		c.holds.active = make(map[int]string)
+	}
+	c.holds.next++
+	id := c.holds.next
+	c.holds.active[id] = reason
+	c.holds.mu.Unlock()
+	logger.LogInfof("Hold: %s started, keeping awake regardless of schedule", reason)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.holds.mu.Lock()
+			delete(c.holds.active, id)
+			c.holds.mu.Unlock()
+			logger.LogInfof("Hold: %s released", reason)
+		})
+	}
+}
+
+// holdReason 回傳目前所有 hold 與暫時覆寫的原因 (以 ", " 連接)，沒有時為空字串
+func (c *Controller) holdReason() string {
+	c.holds.mu.Lock()
+	reasons := make([]string, 0, len(c.holds.active)+1)
+	for _, r := range c.holds.active {
+		reasons = append(reasons, r)
+	}
+	c.holds.mu.Unlock()
+	if r := c.overrideReason(); r != "" {
+		reasons = append(reasons, r)
+	}
+	sort.Strings(reasons)
+	return strings.Join(reasons, ", ")
+}
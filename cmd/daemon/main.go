@@ -2,15 +2,20 @@ package main
 
 import (
 	_ "embed"
+	"flag"
 	"fmt"
 	"io"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/HanksJCTsai/goidleguard/internal/condition"
 	"github.com/HanksJCTsai/goidleguard/internal/config"
+	"github.com/HanksJCTsai/goidleguard/internal/preventidle"
 	"github.com/HanksJCTsai/goidleguard/pkg/logger"
 	"github.com/getlantern/systray"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -27,6 +32,21 @@ const (
 )
 
 func main() {
+	// goidleguard run [flags] -- <command>：只在指令執行期間防閒置，不啟動系統匣
+	if len(os.Args) > 1 && os.Args[1] == "run" {
+		os.Exit(runCLI(os.Args[2:]))
+	}
+	// goidleguard override [<duration>|<HH:MM>|off]：設定執行中 daemon 的暫時覆寫
+	if len(os.Args) > 1 && os.Args[1] == "override" {
+		override := condition.NewOverride(condition.OverridePath(resolveAppRoot()))
+		os.Exit(overrideCLI(override, os.Args[2:], time.Now(), os.Stdout, os.Stderr))
+	}
+
+	dryRun := flag.Bool("dry-run", false, "log what would be simulated instead of injecting input (same as backend: dryrun)")
+	waitPID := flag.Int("wait-pid", 0, "keep awake until the process with this pid exits, then return to schedule mode (Linux only)")
+	exitAfterWait := flag.Bool("exit", false, "with -wait-pid, quit once the process exits instead of returning to schedule mode")
+	flag.Parse()
+
 	// 1. 確保 Log 檔案跟執行檔在同一層目錄
 	appRoot := resolveAppRoot()
 
@@ -66,9 +86,26 @@ func main() {
 	logger.LogInfo("Config loaded successfully. Path: ", configPath)
 
 	// 建立並啟動 DaemonController
-	dc := NewController(cfg)
+	dc, err := openController(cfg, *dryRun)
+	if err != nil {
+		os.Exit(1)
+	}
+	dc.SetOverride(condition.NewOverride(condition.OverridePath(appRoot)))
+	var waitDone <-chan struct{}
+	if *waitPID != 0 {
+		if waitDone, err = dc.WaitPID(*waitPID); err != nil {
+			logger.LogError("Failed to wait for pid:", err)
+			os.Exit(1)
+		}
+	}
 	onReady := func() {
 		setupTrayItems(dc, logPath, configPath)
+		if waitDone != nil && *exitAfterWait {
+			go func() {
+				<-waitDone
+				systray.Quit()
+			}()
+		}
 	}
 
 	// Define onExit logic / 定義程式退出時的邏輯
@@ -83,16 +120,116 @@ func main() {
 	systray.Run(onReady, onExit)
 }
 
+// openController 依設定開啟 backend 並建立 Controller，dryRun 時改用 dry-run backend；錯誤已寫入 log
+func openController(cfg *config.APPConfig, dryRun bool) (*Controller, error) {
+	if dryRun {
+		cfg.IdlePrevention.Backend = preventidle.DryRunBackendName
+	}
+	if cfg.IdlePrevention.Backend == preventidle.DryRunBackendName {
+		logger.LogInfo("=== Dry run: no input will be injected ===")
+	}
+
+	backend, err := preventidle.OpenBackend(cfg.IdlePrevention.Backend)
+	if err != nil {
+		logger.LogError("Failed to open idle prevention backend:", err)
+		return nil, err
+	}
+	dc, err := NewController(cfg, backend)
+	if err != nil {
+		logger.LogError("Failed to set up idle prevention strategies:", err)
+		return nil, err
+	}
+	return dc, nil
+}
+
+// runCLI 實作 `goidleguard run [flags] -- <command> [args...]`，回傳要結束的 exit code。
+// log 寫到 stderr，避免與子程序的 stdout 混在一起。
+func runCLI(args []string) int {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	mode := fs.String("mode", "", "idle prevention mode while the command runs (default: mode from config.yaml)")
+	dryRun := fs.Bool("dry-run", false, "log what would be simulated instead of injecting input")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s run [flags] -- <command> [args...]\n", filepath.Base(os.Args[0]))
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	argv := fs.Args()
+	if len(argv) == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	logger.SetOutput(os.Stderr)
+	configPath := filepath.Join(resolveAppRoot(), ConfFileName)
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		logger.LogError("Failed to load config:", err)
+		return 1
+	}
+	if *mode != "" {
+		cfg.IdlePrevention.Mode = *mode
+		if err := config.ValidateConfig(cfg); err != nil {
+			logger.LogError("Invalid -mode:", err)
+			return 2
+		}
+	}
+	dc, err := openController(cfg, *dryRun)
+	if err != nil {
+		return 1
+	}
+
+	code, err := runWithPrevention(dc, argv, os.Stdin, os.Stdout, os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s run: %v\n", filepath.Base(os.Args[0]), err)
+	}
+	return code
+}
+
 // 輔助函式：為了讓 main 更乾淨，可以把 systray 設定放這裡
 func setupTrayItems(dc *Controller, logPath, configPath string) {
 	systray.SetIcon(iconData)
 	if runtime.GOOS != "darwin" {
 		systray.SetTitle("GoIdleGuard")
 	}
-	systray.SetTooltip("GoIdleGuard is running")
+	// 提示中顯示目前使用的防閒置策略，降級時同步更新
+	setTooltip := func(strategy string) {
+		systray.SetTooltip(fmt.Sprintf("%s (strategy: %s)", AppTooltip, strategy))
+	}
+	setTooltip(dc.ActiveStrategy())
+	dc.OnStrategyChange(setTooltip)
+
+	// 重試用盡的事件顯示在提示中，直到策略改變
+	go func() {
+		for ev := range dc.Events() {
+			if ev.Kind == EventSimulationCancelled {
+				// 使用者回來而取消的模擬輸入不是錯誤，只記錄
+				logger.LogInfof("Event %s: %s (strategy: %s): %v", ev.Kind, ev.Op, ev.Strategy, ev.Err)
+				continue
+			}
+			logger.LogErrorf("Event %s: %s (strategy: %s): %v", ev.Kind, ev.Op, ev.Strategy, ev.Err)
+			systray.SetTooltip(fmt.Sprintf("%s (strategy: %s) - %s failing", AppTooltip, ev.Strategy, ev.Op))
+		}
+	}()
 
 	mShowLogs := systray.AddMenuItem("Show Logs (Live)", "Open log viewer")
 	systray.AddSeparator()
+	mKeepAwake := systray.AddMenuItem("Keep Awake", "Keep awake regardless of the work schedule")
+	overrideItems := map[*systray.MenuItem]time.Duration{}
+	for _, d := range []time.Duration{30 * time.Minute, time.Hour, 2 * time.Hour, 4 * time.Hour} {
+		item := mKeepAwake.AddSubMenuItem("For "+shortDuration(d), "Keep awake for "+shortDuration(d))
+		overrideItems[item] = d
+	}
+	mCancelOverride := mKeepAwake.AddSubMenuItem("Cancel", "Return to the work schedule")
+	for item, d := range overrideItems {
+		go func(item *systray.MenuItem, d time.Duration) {
+			for range item.ClickedCh {
+				if err := dc.StartOverride(time.Now().Add(d)); err != nil {
+					logger.LogError("Failed to start override:", err)
+				}
+			}
+		}(item, d)
+	}
+	systray.AddSeparator()
 	mSettings := systray.AddMenuItem("Settings", "Open config.yaml")
 	mAbout := systray.AddMenuItem("About", "About GoIdleGuard")
 	systray.AddSeparator()
@@ -103,6 +240,10 @@ func setupTrayItems(dc *Controller, logPath, configPath string) {
 			select {
 			case <-mShowLogs.ClickedCh:
 				openLogViewer(logPath)
+			case <-mCancelOverride.ClickedCh:
+				if err := dc.CancelOverride(); err != nil {
+					logger.LogError("Failed to cancel override:", err)
+				}
 			case <-mSettings.ClickedCh:
 				openFile(configPath)
 			case <-mAbout.ClickedCh:
@@ -126,6 +267,18 @@ func setupTrayItems(dc *Controller, logPath, configPath string) {
 	}()
 }
 
+// shortDuration 將選單中的時長顯示為 "30m"、"1h" 而不是 "1h0m0s"
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
 // resolveAppRoot 尋找正確的應用程式目錄
 // 優先順序：
 // 1. 執行檔所在目錄 (適合正式部署，已有 config.yaml)
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/HanksJCTsai/goidleguard/internal/condition"
+	"github.com/HanksJCTsai/goidleguard/pkg/logger"
+)
+
+// overrideTimeLayout 為 log 與狀態中顯示覆寫到期時間的格式
+const overrideTimeLayout = "2006-01-02 15:04"
+
+// SetOverride 設定暫時覆寫使用的檔案，未設定時不支援覆寫
+func (c *Controller) SetOverride(o *condition.Override) {
+	c.override = o
+}
+
+// StartOverride 開始暫時覆寫，until 前不論工作時段都持續防閒置，於下一次 tick 生效
+func (c *Controller) StartOverride(until time.Time) error {
+	if c.override == nil {
+		return fmt.Errorf("override is not configured")
+	}
+	if err := c.override.Set(until); err != nil {
+		return err
+	}
+	logger.LogInfof("Override: keeping awake until %s", until.Format(overrideTimeLayout))
+	return nil
+}
+
+// CancelOverride 取消暫時覆寫，回到依排程運作
+func (c *Controller) CancelOverride() error {
+	if c.override == nil {
+		return nil
+	}
+	if err := c.override.Clear(); err != nil {
+		return err
+	}
+	logger.LogInfo("Override: cancelled, returning to schedule")
+	return nil
+}
+
+// overrideReason 回傳有效的暫時覆寫 (當作 hold 的原因)，沒有覆寫或已到期時為空字串
+func (c *Controller) overrideReason() string {
+	if c.override == nil {
+		return ""
+	}
+	until, active, expired, err := c.override.Active(c.now())
+	switch {
+	case err != nil:
+		logger.LogError("Override: failed to read override:", err)
+	case expired:
+		logger.LogInfof("Override: expired at %s, returning to schedule", until.Format(overrideTimeLayout))
+	case active:
+		return "override until " + until.Format(overrideTimeLayout)
+	}
+	return ""
+}
+
+// overrideCLI 實作 `goidleguard override [<duration>|<HH:MM>|<RFC 3339>|off]`：
+// 寫入 o 的檔案後由執行中的 daemon 在下一次 tick 套用；沒有參數時顯示目前的覆寫
+func overrideCLI(o *condition.Override, args []string, now time.Time, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("override", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: override [<duration> | <HH:MM> | <RFC 3339 time> | off]")
+		fmt.Fprintln(stderr, "  e.g. override 2h, override 18:30, override off; without arguments, show the current override")
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	switch fs.NArg() {
+	case 0:
+		until, active, _, err := o.Active(now)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		if !active {
+			fmt.Fprintln(stdout, "No override active")
+			return 0
+		}
+		fmt.Fprintf(stdout, "Keeping awake until %s\n", until.Format(overrideTimeLayout))
+		return 0
+	case 1:
+	default:
+		fs.Usage()
+		return 2
+	}
+
+	if arg := fs.Arg(0); arg == "off" || arg == "cancel" {
+		if err := o.Clear(); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		fmt.Fprintln(stdout, "Override cancelled")
+		return 0
+	}
+	until, err := condition.ParseUntil(fs.Arg(0), now)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if err := o.Set(until); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Keeping awake until %s\n", until.Format(overrideTimeLayout))
+	return 0
+}
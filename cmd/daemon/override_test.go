@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/HanksJCTsai/goidleguard/internal/condition"
+)
+
+func TestController_OverrideOutsideWorkTime(t *testing.T) {
+	now := mondayLunch
+	ctrl, fake := newFakeController("key", now)
+	ctrl.now = func() time.Time { return now }
+	ctrl.SetOverride(condition.NewOverride(condition.OverridePath(t.TempDir())))
+
+	if err := ctrl.StartOverride(now.Add(15 * time.Minute)); err != nil {
+		t.Fatalf("StartOverride failed: %v", err)
+	}
+	fake.SetIdle(2 * time.Minute)
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 1 {
+		t.Fatalf("Expected input outside work time during an override, got %v", got)
+	}
+
+	// 到期後回到依排程運作
+	now = now.Add(15 * time.Minute)
+	fake.SetIdle(2 * time.Minute)
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 1 {
+		t.Errorf("Expected no input after the override expired, got %v", got)
+	}
+	if got := ctrl.holdReason(); got != "" {
+		t.Errorf("Expected no hold after the override expired, got %q", got)
+	}
+}
+
+func TestController_CancelOverride(t *testing.T) {
+	ctrl, _ := newFakeController("key", mondayLunch)
+	ctrl.SetOverride(condition.NewOverride(condition.OverridePath(t.TempDir())))
+
+	if err := ctrl.StartOverride(mondayLunch.Add(time.Hour)); err != nil {
+		t.Fatalf("StartOverride failed: %v", err)
+	}
+	if got := ctrl.holdReason(); got == "" {
+		t.Fatal("Expected the override to count as a hold")
+	}
+	if err := ctrl.CancelOverride(); err != nil {
+		t.Fatalf("CancelOverride failed: %v", err)
+	}
+	if got := ctrl.holdReason(); got != "" {
+		t.Errorf("Expected no hold after cancelling, got %q", got)
+	}
+}
+
+func TestOverrideCLI(t *testing.T) {
+	o := condition.NewOverride(condition.OverridePath(t.TempDir()))
+	now := time.Date(2025, 1, 6, 20, 0, 0, 0, time.Local)
+	run := func(args ...string) (int, string) {
+		var stdout, stderr bytes.Buffer
+		code := overrideCLI(o, args, now, &stdout, &stderr)
+		return code, stdout.String() + stderr.String()
+	}
+
+	if code, out := run("2h"); code != 0 || out != "Keeping awake until 2025-01-06 22:00\n" {
+		t.Errorf("override 2h = %d %q", code, out)
+	}
+	if code, out := run(); code != 0 || out != "Keeping awake until 2025-01-06 22:00\n" {
+		t.Errorf("override (status) = %d %q", code, out)
+	}
+	if code, out := run("off"); code != 0 || out != "Override cancelled\n" {
+		t.Errorf("override off = %d %q", code, out)
+	}
+	if code, out := run(); code != 0 || out != "No override active\n" {
+		t.Errorf("override (status) = %d %q", code, out)
+	}
+	if code, _ := run("soon"); code != 2 {
+		t.Errorf("Expected exit code 2 for an invalid override, got %d", code)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// 依 shell 慣例，指令找不到為 127、無法執行為 126，被 signal 結束為 128+signal
+const (
+	exitNotFound    = 127
+	exitNotRunnable = 126
+	exitSignalBase  = 128
+)
+
+// runWithPrevention 在 argv 指令執行期間持續防閒置 (類似 macOS 的 caffeinate)：
+// 以 hold 讓 Controller 不受工作時段限制，子程序繼承 stdio，結束後停止防閒置並回傳子程序的 exit code。
+// 子程序與我們在同一個 process group，終端機的 Ctrl-C 已經直接送到子程序，因此 SIGINT 只攔截不轉送
+// (重複送出會讓部分工具視為強制結束)；SIGTERM 通常只送給我們，轉送給子程序。兩者都等子程序結束後再返回。
+func runWithPrevention(c *Controller, argv []string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	if len(argv) == 0 {
+		return 2, errors.New("no command given")
+	}
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdin, stdout, stderr
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	if err := cmd.Start(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return exitNotFound, err
+		}
+		return exitNotRunnable, err
+	}
+
+	release := c.Hold("run " + strings.Join(argv, " "))
+	// 先同步執行一次 tick，讓防閒置立即生效，而不是等到第一個排程週期
+	c.tick()
+	c.StartDaemon()
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-sigCh:
+				if sig == syscall.SIGTERM {
+					cmd.Process.Signal(sig)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	err := cmd.Wait()
+	close(done)
+
+	c.StopDaemon()
+	release()
+	if err != nil && cmd.ProcessState == nil {
+		return 1, fmt.Errorf("wait for %s failed: %w", argv[0], err)
+	}
+	return exitCode(cmd.ProcessState), nil
+}
+
+// exitCode 回傳子程序的 exit code，被 signal 結束時依 shell 慣例回傳 128+signal
+func exitCode(ps *os.ProcessState) int {
+	if ws, ok := ps.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return exitSignalBase + int(ws.Signal())
+	}
+	return ps.ExitCode()
+}
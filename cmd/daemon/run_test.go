@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestHelperProcess 不是真正的測試，而是 runWithPrevention 要執行的子程序
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GOIDLEGUARD_HELPER_PROCESS") != "1" {
+		return
+	}
+	if os.Getenv("GOIDLEGUARD_HELPER_SIGNALS") == "1" {
+		// 回報收到的 signal 次數
+		sigCh := make(chan os.Signal, 4)
+		signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+		os.Stdout.WriteString("ready\n")
+		counts := map[os.Signal]int{}
+		timeout := time.After(500 * time.Millisecond)
+		for {
+			select {
+			case sig := <-sigCh:
+				counts[sig]++
+			case <-timeout:
+				fmt.Printf("int=%d term=%d\n", counts[os.Interrupt], counts[syscall.SIGTERM])
+				os.Exit(0)
+			}
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	os.Stdout.WriteString("hello from child\n")
+	code, _ := strconv.Atoi(os.Getenv("GOIDLEGUARD_HELPER_EXIT"))
+	os.Exit(code)
+}
+
+func helperArgv() []string {
+	return []string{os.Args[0], "-test.run=^TestHelperProcess$"}
+}
+
+func TestRunWithPrevention(t *testing.T) {
+	// 午休時段，沒有 hold 時不會防閒置
+	ctrl, fake := newFakeController("inhibit", mondayLunch)
+	t.Setenv("GOIDLEGUARD_HELPER_PROCESS", "1")
+	t.Setenv("GOIDLEGUARD_HELPER_EXIT", "3")
+
+	var stdout bytes.Buffer
+	code, err := runWithPrevention(ctrl, helperArgv(), nil, &stdout, os.Stderr)
+	if err != nil {
+		t.Fatalf("runWithPrevention failed: %v", err)
+	}
+	if code != 3 {
+		t.Errorf("Expected the child's exit code 3, got %d", code)
+	}
+	if !bytes.Contains(stdout.Bytes(), []byte("hello from child")) {
+		t.Errorf("Expected child stdout to be passed through, got %q", stdout.String())
+	}
+	held, inhibits, releases := fake.Inhibited()
+	if held || inhibits != 1 || releases != 1 {
+		t.Errorf("Expected the power assertion to be held for the command's lifetime only, held=%v inhibits=%d releases=%d", held, inhibits, releases)
+	}
+	if got := ctrl.holdReason(); got != "" {
+		t.Errorf("Expected the hold to be released, got %q", got)
+	}
+}
+
+func TestRunWithPrevention_NotFound(t *testing.T) {
+	ctrl, fake := newFakeController("inhibit", mondayLunch)
+
+	code, err := runWithPrevention(ctrl, []string{"goidleguard-no-such-command"}, nil, nil, nil)
+	if err == nil || code != exitNotFound {
+		t.Errorf("Expected exit code %d and an error, got %d (%v)", exitNotFound, code, err)
+	}
+	if _, inhibits, _ := fake.Inhibited(); inhibits != 0 {
+		t.Error("Expected no prevention when the command cannot start")
+	}
+}
+
+func TestController_HoldOutsideWorkTime(t *testing.T) {
+	ctrl, fake := newFakeController("key", mondayLunch)
+	ctrl.cfg.IdlePrevention.AbsenceTimeout = time.Second
+
+	release := ctrl.Hold("test")
+	fake.SetIdle(2 * time.Second)
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 1 {
+		t.Fatalf("Expected input during a hold outside work time, got %v", got)
+	}
+
+	release()
+	release()
+	fake.SetIdle(2 * time.Second)
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 1 {
+		t.Errorf("Expected no input after the hold was released, got %v", got)
+	}
+}
+
+// syncBuffer 讓測試可以在子程序寫入 stdout 的同時讀取
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestRunWithPrevention_Signals(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals cannot be sent to a process on Windows")
+	}
+	ctrl, _ := newFakeController("inhibit", mondayLunch)
+	t.Setenv("GOIDLEGUARD_HELPER_PROCESS", "1")
+	t.Setenv("GOIDLEGUARD_HELPER_SIGNALS", "1")
+
+	var stdout syncBuffer
+	done := make(chan int)
+	go func() {
+		code, err := runWithPrevention(ctrl, helperArgv(), nil, &stdout, os.Stderr)
+		if err != nil {
+			t.Errorf("runWithPrevention failed: %v", err)
+		}
+		done <- code
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(stdout.String(), "ready") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// 只送給我們 (例如 kill)：SIGINT 在終端機上已經送到整個 process group，不轉送；SIGTERM 轉送
+	self, _ := os.FindProcess(os.Getpid())
+	self.Signal(os.Interrupt)
+	self.Signal(syscall.SIGTERM)
+	<-done
+
+	if got := stdout.String(); !strings.Contains(got, "int=0 term=1") {
+		t.Errorf("Expected only SIGTERM to be forwarded once, got %q", got)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/HanksJCTsai/goidleguard/internal/condition"
+	"github.com/HanksJCTsai/goidleguard/pkg/logger"
+)
+
+// waitPIDInterval 為 -wait-pid 輪詢 /proc 的間隔
+const waitPIDInterval = time.Second
+
+// WaitPID 在 pid 執行期間以 hold 持續防閒置 (僅 Linux，輪詢 /proc)，
+// pid 結束後釋放 hold 並關閉回傳的 channel，之後回到依排程運作。
+// 以程序的啟動時間辨識同一個程序，pid 被其他程序重新使用時也視為已結束。
+// StopDaemon 時停止輪詢並釋放 hold，回傳的 channel 不會被關閉
+func (c *Controller) WaitPID(pid int) (<-chan struct{}, error) {
+	if runtime.GOOS != "linux" {
+		return nil, errors.New("-wait-pid is only supported on Linux")
+	}
+	return c.waitPID(condition.DefaultProcRoot, pid, waitPIDInterval)
+}
+
+func (c *Controller) waitPID(root string, pid int, interval time.Duration) (<-chan struct{}, error) {
+	if pid <= 0 {
+		return nil, fmt.Errorf("invalid pid %d", pid)
+	}
+	start, ok := condition.PIDStartTime(root, pid)
+	if !ok {
+		return nil, fmt.Errorf("process %d is not running", pid)
+	}
+
+	release := c.Hold(fmt.Sprintf("wait-pid %d", pid))
+	done := make(chan struct{})
+	stopped := c.stopped
+	go func() {
+		defer release()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopped:
+				logger.LogInfof("WaitPID: daemon stopped, no longer waiting for process %d", pid)
+				return
+			case <-ticker.C:
+			}
+			if now, ok := condition.PIDStartTime(root, pid); !ok || now != start {
+				logger.LogInfof("WaitPID: process %d exited", pid)
+				close(done)
+				return
+			}
+		}
+	}()
+	return done, nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// procStat 組出 starttime (第 22 欄) 為 start 的 /proc/<pid>/stat 內容
+func procStat(pid int, comm string, start uint64) string {
+	return fmt.Sprintf("%d (%s) S 1 %d %d 0 -1 4194560 100 0 0 0 1 2 0 0 20 0 1 0 %d 123456 789", pid, comm, pid, pid, start)
+}
+
+func TestController_WaitPID(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"4242/stat": procStat(4242, "build", 5000)})
+
+	ctrl, fake := newFakeController("key", mondayLunch)
+	done, err := ctrl.waitPID(root, 4242, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("waitPID failed: %v", err)
+	}
+	fake.SetIdle(2 * time.Minute)
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 1 {
+		t.Fatalf("Expected input outside work time while the process runs, got %v", got)
+	}
+
+	if err := os.RemoveAll(filepath.Join(root, "4242")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected waitPID to finish after the process exited")
+	}
+	if got := ctrl.holdReason(); got != "" {
+		t.Errorf("Expected the hold to be released, got %q", got)
+	}
+	fake.SetIdle(2 * time.Minute)
+	ctrl.tick()
+	if got := fake.Inputs(); len(got) != 1 {
+		t.Errorf("Expected schedule mode after the process exited, got %v", got)
+	}
+}
+
+func TestController_WaitPIDReused(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"4242/stat": procStat(4242, "build", 5000)})
+
+	ctrl, _ := newFakeController("key", mondayLunch)
+	done, err := ctrl.waitPID(root, 4242, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("waitPID failed: %v", err)
+	}
+	// 原程序結束後 pid 被另一個程序重新使用
+	writeFiles(t, root, map[string]string{"4242/stat": procStat(4242, "other", 9000)})
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected waitPID to finish when the pid was reused by another process")
+	}
+	if got := ctrl.holdReason(); got != "" {
+		t.Errorf("Expected the hold to be released, got %q", got)
+	}
+}
+
+func TestController_WaitPIDStopped(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"4242/stat": procStat(4242, "build", 5000)})
+
+	ctrl, _ := newFakeController("key", mondayLunch)
+	ctrl.StartDaemon()
+	done, err := ctrl.waitPID(root, 4242, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("waitPID failed: %v", err)
+	}
+	// 程序仍在執行時停止 daemon，hold 也應一併釋放
+	ctrl.StopDaemon()
+	deadline := time.Now().Add(time.Second)
+	for ctrl.holdReason() != "" {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the hold to be released after StopDaemon, got %q", ctrl.holdReason())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	select {
+	case <-done:
+		t.Error("Expected done to stay open while the process is still running")
+	default:
+	}
+}
+
+func TestController_WaitPIDNotRunning(t *testing.T) {
+	ctrl, _ := newFakeController("key", mondayLunch)
+	if _, err := ctrl.waitPID(t.TempDir(), 4242, time.Millisecond); err == nil {
+		t.Error("Expected an error for a process that is not running")
+	}
+	if got := ctrl.holdReason(); got != "" {
+		t.Errorf("Expected no hold, got %q", got)
+	}
+}
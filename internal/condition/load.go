@@ -0,0 +1,142 @@
+package condition
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HanksJCTsai/goidleguard/internal/config"
+)
+
+// 負載指標名稱
+const (
+	LoadMetricLoad1 = "load1"
+	LoadMetricLoad5 = "load5"
+	LoadMetricCPU   = "cpu"
+)
+
+const (
+	// defaultLoadLowRatio 為未設定 Low 時解除門檻佔 High 的比例
+	defaultLoadLowRatio = 0.8
+	defaultCPUWindow    = time.Minute
+)
+
+// NewLoad 由設定建立 Load 並補上預設值，root 為空字串時使用 DefaultProcRoot
+func NewLoad(root string, cfg config.SystemLoadConfig) *Load {
+	if root == "" {
+		root = DefaultProcRoot
+	}
+	l := &Load{Root: root, Metric: cfg.Metric, High: cfg.High, Low: cfg.Low, Window: cfg.Window}
+	if l.Metric == "" {
+		l.Metric = LoadMetricLoad1
+	}
+	if l.Low <= 0 {
+		l.Low = l.High * defaultLoadLowRatio
+	}
+	if l.Window <= 0 {
+		l.Window = defaultCPUWindow
+	}
+	return l
+}
+
+// Enabled 回傳是否設定了負載門檻
+func (l *Load) Enabled() bool {
+	return l.High > 0
+}
+
+// Value 回傳目前的負載：load1 / load5 為 loadavg，cpu 為 Window 內的 CPU 使用率 (%)。
+// cpu 需要兩次取樣才能計算，第一次呼叫回傳 0
+func (l *Load) Value(now time.Time) (float64, error) {
+	switch l.Metric {
+	case LoadMetricLoad1, LoadMetricLoad5:
+		return l.loadavg()
+	case LoadMetricCPU:
+		return l.cpuUsage(now)
+	}
+	return 0, fmt.Errorf("unknown load metric %q", l.Metric)
+}
+
+// Above 以兩個門檻判斷負載是否偏高：原本不高時需達到 High，原本偏高時要低於 Low 才解除
+func (l *Load) Above(high bool, value float64) bool {
+	if high {
+		return value >= l.Low
+	}
+	return value >= l.High
+}
+
+// loadavg 讀取 /proc/loadavg，格式為 "0.52 0.58 0.59 1/1234 5678"
+func (l *Load) loadavg() (float64, error) {
+	path := filepath.Join(l.Root, "loadavg")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("read %s failed: %w", path, err)
+	}
+	fields := strings.Fields(string(data))
+	i := 0
+	if l.Metric == LoadMetricLoad5 {
+		i = 1
+	}
+	if len(fields) <= i {
+		return 0, fmt.Errorf("unexpected %s format: %q", path, data)
+	}
+	value, err := strconv.ParseFloat(fields[i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse %s failed: %w", path, err)
+	}
+	return value, nil
+}
+
+// cpuUsage 加入一次 /proc/stat 取樣，回傳與 Window 開始前最後一個取樣之間的 CPU 使用率
+func (l *Load) cpuUsage(now time.Time) (float64, error) {
+	s, err := readCPUSample(filepath.Join(l.Root, "stat"))
+	if err != nil {
+		return 0, err
+	}
+	s.at = now
+	l.samples = append(l.samples, s)
+
+	// 保留 Window 內的取樣，以及 Window 開始前的最後一個取樣作為基準
+	i := 0
+	for i+1 < len(l.samples) && !l.samples[i+1].at.After(now.Add(-l.Window)) {
+		i++
+	}
+	l.samples = l.samples[i:]
+	base := l.samples[0]
+	if len(l.samples) < 2 || s.total <= base.total {
+		return 0, nil
+	}
+	return 100 * float64(s.busy-base.busy) / float64(s.total-base.total), nil
+}
+
+// readCPUSample 讀取 /proc/stat 第一行的 cpu 總計：
+// user nice system idle iowait irq softirq steal (guest 已計入 user，不重複加總)
+func readCPUSample(path string) (cpuSample, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cpuSample{}, fmt.Errorf("read %s failed: %w", path, err)
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	fields := strings.Fields(line)
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return cpuSample{}, fmt.Errorf("unexpected %s format: %q", path, line)
+	}
+	var s cpuSample
+	for i, f := range fields[1:] {
+		if i >= 8 {
+			break
+		}
+		v, err := strconv.ParseUint(f, 10, 64)
+		if err != nil {
+			return cpuSample{}, fmt.Errorf("parse %s failed: %w", path, err)
+		}
+		s.total += v
+		// idle 與 iowait 不算忙碌
+		if i != 3 && i != 4 {
+			s.busy += v
+		}
+	}
+	return s, nil
+}
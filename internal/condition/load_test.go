@@ -0,0 +1,81 @@
+package condition
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/HanksJCTsai/goidleguard/internal/config"
+)
+
+func writeProcFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+}
+
+func TestLoad_Loadavg(t *testing.T) {
+	root := t.TempDir()
+	writeProcFile(t, root, "loadavg", "3.50 2.25 1.00 4/1234 5678\n")
+
+	for metric, want := range map[string]float64{"": 3.5, "load1": 3.5, "load5": 2.25} {
+		got, err := NewLoad(root, config.SystemLoadConfig{Metric: metric, High: 3}).Value(time.Now())
+		if err != nil || got != want {
+			t.Errorf("Metric %q: expected %v, got %v (%v)", metric, want, got, err)
+		}
+	}
+}
+
+func TestLoad_CPUWindow(t *testing.T) {
+	root := t.TempDir()
+	l := NewLoad(root, config.SystemLoadConfig{Metric: "cpu", High: 70, Window: time.Minute})
+	start := time.Date(2025, 1, 6, 20, 0, 0, 0, time.Local)
+
+	// 欄位：user nice system idle iowait irq softirq steal guest guest_nice
+	steps := []struct {
+		stat string
+		at   time.Duration
+		want float64
+	}{
+		{stat: "cpu  100 0 0 900 0 0 0 0 0 0", at: 0, want: 0},
+		// 30 秒內 busy +75、idle +25 (其中 iowait 5)
+		{stat: "cpu  170 0 5 920 5 0 0 0 50 0", at: 30 * time.Second, want: 75},
+		// 基準仍是第一個取樣：busy +100、總計 +200
+		{stat: "cpu  190 0 10 995 5 0 0 0 50 0", at: time.Minute, want: 50},
+		// 第一個取樣已在視窗外，改以 30 秒的取樣為基準：busy +25、總計 +200
+		{stat: "cpu  190 0 10 1090 10 0 0 0 50 0", at: 90 * time.Second, want: 12.5},
+	}
+	for i, s := range steps {
+		writeProcFile(t, root, "stat", s.stat+"\ncpu0 1 2 3 4\n")
+		got, err := l.Value(start.Add(s.at))
+		if err != nil || got != s.want {
+			t.Errorf("Step %d: expected %v%%, got %v (%v)", i, s.want, got, err)
+		}
+	}
+}
+
+func TestLoad_Hysteresis(t *testing.T) {
+	l := NewLoad("", config.SystemLoadConfig{High: 4})
+	if l.Low != 3.2 {
+		t.Fatalf("Expected low to default to 80%% of high, got %v", l.Low)
+	}
+
+	high := false
+	for _, step := range []struct {
+		value float64
+		want  bool
+	}{
+		{3.9, false},
+		{4.0, true},
+		{3.5, true}, // 介於兩個門檻之間，維持原狀
+		{3.1, false},
+		{3.5, false},
+	} {
+		high = l.Above(high, step.value)
+		if high != step.want {
+			t.Errorf("Value %v: expected high=%v", step.value, step.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package condition
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// OverrideFileName 為 appRoot 下記錄暫時覆寫到期時間的檔案名稱
+const OverrideFileName = "override"
+
+// NewOverride 建立以 path 記錄到期時間的 Override
+func NewOverride(path string) *Override {
+	return &Override{Path: path}
+}
+
+// OverridePath 回傳 appRoot 下的覆寫檔案路徑
+func OverridePath(appRoot string) string {
+	return filepath.Join(appRoot, OverrideFileName)
+}
+
+// Set 設定覆寫到 until 為止，先寫入暫存檔再 rename，避免 daemon 讀到寫到一半的檔案
+func (o *Override) Set(until time.Time) error {
+	tmp := o.Path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(until.Format(time.RFC3339)+"\n"), 0644); err != nil {
+		return fmt.Errorf("write %s failed: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, o.Path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("rename %s failed: %w", tmp, err)
+	}
+	return nil
+}
+
+// Clear 取消覆寫，沒有覆寫時不是錯誤
+func (o *Override) Clear() error {
+	if err := os.Remove(o.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove %s failed: %w", o.Path, err)
+	}
+	return nil
+}
+
+// Until 回傳覆寫的到期時間，沒有覆寫時為零值
+func (o *Override) Until() (time.Time, error) {
+	data, err := os.ReadFile(o.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("read %s failed: %w", o.Path, err)
+	}
+	until, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse %s failed: %w", o.Path, err)
+	}
+	return until, nil
+}
+
+// Active 回傳覆寫在 now 是否仍有效；已到期的覆寫會被清除，expired 為 true 表示這次呼叫清除了它
+func (o *Override) Active(now time.Time) (until time.Time, active, expired bool, err error) {
+	until, err = o.Until()
+	if err != nil || until.IsZero() {
+		return until, false, false, err
+	}
+	if now.Before(until) {
+		return until, true, false, nil
+	}
+	return until, false, true, o.Clear()
+}
+
+// ParseUntil 解析覆寫的到期時間：時長 (例如 "90m"、"2h")、當地時間 "15:04"
+// (已經過了就是明天的這個時間) 或 RFC 3339 時間
+func ParseUntil(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if d, err := time.ParseDuration(s); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("override duration must be positive, got %s", s)
+		}
+		return now.Add(d), nil
+	}
+	if t, err := time.ParseInLocation("15:04", s, now.Location()); err == nil {
+		until := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !until.After(now) {
+			until = until.AddDate(0, 0, 1)
+		}
+		return until, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		if !t.After(now) {
+			return time.Time{}, fmt.Errorf("override time %s is in the past", s)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid override %q: expected a duration (2h), a time of day (18:30) or an RFC 3339 time", s)
+}
@@ -0,0 +1,77 @@
+package condition
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOverride_SetAndExpire(t *testing.T) {
+	o := NewOverride(OverridePath(t.TempDir()))
+	now := time.Date(2025, 1, 6, 20, 0, 0, 0, time.Local)
+
+	if _, active, _, err := o.Active(now); err != nil || active {
+		t.Fatalf("Expected no override without a file, got active=%v err=%v", active, err)
+	}
+
+	if err := o.Set(now.Add(time.Hour)); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	// 以新的 Override 讀取，模擬 daemon 重新啟動
+	o = NewOverride(o.Path)
+	until, active, _, err := o.Active(now.Add(30 * time.Minute))
+	if err != nil || !active || !until.Equal(now.Add(time.Hour)) {
+		t.Fatalf("Expected an override until %v, got %v active=%v err=%v", now.Add(time.Hour), until, active, err)
+	}
+
+	if _, active, expired, err := o.Active(now.Add(time.Hour)); err != nil || active || !expired {
+		t.Fatalf("Expected the override to expire, got active=%v expired=%v err=%v", active, expired, err)
+	}
+	if until, err := o.Until(); err != nil || !until.IsZero() {
+		t.Errorf("Expected the expired override to be cleared, got %v (%v)", until, err)
+	}
+}
+
+func TestOverride_Clear(t *testing.T) {
+	o := NewOverride(OverridePath(t.TempDir()))
+	if err := o.Clear(); err != nil {
+		t.Errorf("Expected Clear without an override to succeed, got %v", err)
+	}
+	if err := o.Set(time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := o.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if _, active, _, _ := o.Active(time.Now()); active {
+		t.Error("Expected no override after Clear")
+	}
+}
+
+func TestParseUntil(t *testing.T) {
+	now := time.Date(2025, 1, 6, 20, 0, 0, 0, time.Local)
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{in: "90m", want: now.Add(90 * time.Minute)},
+		{in: "22:30", want: time.Date(2025, 1, 6, 22, 30, 0, 0, time.Local)},
+		{in: "08:00", want: time.Date(2025, 1, 7, 8, 0, 0, 0, time.Local)},
+		{in: now.Add(time.Hour).Format(time.RFC3339), want: now.Add(time.Hour)},
+		{in: now.Add(-time.Hour).Format(time.RFC3339), wantErr: true},
+		{in: "-1h", wantErr: true},
+		{in: "tonight", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseUntil(tt.in, now)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseUntil(%q) expected an error, got %v", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseUntil(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package condition
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/HanksJCTsai/goidleguard/internal/config"
+)
+
+// DefaultPowerSupplyRoot 為 Linux 上 power_supply 的 sysfs 目錄
+const DefaultPowerSupplyRoot = "/sys/class/power_supply"
+
+// NewPower 由設定建立 Power，root 為空字串時使用 DefaultPowerSupplyRoot
+func NewPower(root string, cfg config.PowerConfig) *Power {
+	if root == "" {
+		root = DefaultPowerSupplyRoot
+	}
+	return &Power{Root: root, ACOnly: cfg.ACOnly, MinBatteryPercent: cfg.MinBatteryPercent}
+}
+
+// Enabled 回傳是否設定了任何電源條件
+func (p *Power) Enabled() bool {
+	return p.ACOnly || p.MinBatteryPercent > 0
+}
+
+// Status 讀取 Root 下所有 power supply：Mains / USB 的 online 表示接上外部電源，
+// Battery 的 capacity 為電量；沒有任何電池時視為接上外部電源。
+func (p *Power) Status() (PowerStatus, error) {
+	entries, err := os.ReadDir(p.Root)
+	if err != nil {
+		return PowerStatus{}, fmt.Errorf("read %s failed: %w", p.Root, err)
+	}
+
+	var status PowerStatus
+	var total, batteries int
+	charging := false
+	for _, e := range entries {
+		dir := filepath.Join(p.Root, e.Name())
+		switch readSysfs(dir, "type") {
+		case "Mains", "USB", "USB_C", "USB_PD":
+			if readSysfs(dir, "online") == "1" {
+				status.OnAC = true
+			}
+		case "Battery":
+			// 週邊裝置 (滑鼠、耳機) 的電池 scope 為 Device，不代表系統電源
+			if readSysfs(dir, "scope") == "Device" {
+				continue
+			}
+			capacity, err := strconv.Atoi(readSysfs(dir, "capacity"))
+			if err != nil {
+				continue
+			}
+			total += capacity
+			batteries++
+			if s := readSysfs(dir, "status"); s == "Charging" || s == "Full" {
+				charging = true
+			}
+		}
+	}
+
+	if batteries == 0 {
+		return PowerStatus{OnAC: true, BatteryPercent: 100}, nil
+	}
+	status.HasBattery = true
+	status.BatteryPercent = total / batteries
+	// 部分機器沒有 Mains 節點，以電池正在充電判斷
+	status.OnAC = status.OnAC || charging
+	return status, nil
+}
+
+// Check 回傳目前的電源是否允許防閒置；不允許時 reason 說明原因
+func (p *Power) Check() (ok bool, reason string, err error) {
+	status, err := p.Status()
+	if err != nil {
+		return false, "", err
+	}
+	if status.OnAC {
+		return true, "", nil
+	}
+	if p.ACOnly {
+		return false, fmt.Sprintf("on battery (%d%%) and acOnly is set", status.BatteryPercent), nil
+	}
+	if p.MinBatteryPercent > 0 && status.BatteryPercent < p.MinBatteryPercent {
+		return false, fmt.Sprintf("battery at %d%% (below %d%%)", status.BatteryPercent, p.MinBatteryPercent), nil
+	}
+	return true, "", nil
+}
+
+// readSysfs 讀取 sysfs 屬性並去除結尾換行，讀取失敗時回傳空字串
+func readSysfs(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
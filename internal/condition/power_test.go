@@ -0,0 +1,89 @@
+package condition
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HanksJCTsai/goidleguard/internal/config"
+)
+
+// writeSupply 在 root 下建立一個假的 power supply 節點
+func writeSupply(t *testing.T, root, name string, attrs map[string]string) {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create %s: %v", dir, err)
+	}
+	for k, v := range attrs {
+		if err := os.WriteFile(filepath.Join(dir, k), []byte(v+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s/%s: %v", name, k, err)
+		}
+	}
+}
+
+func TestPower_Status(t *testing.T) {
+	root := t.TempDir()
+	writeSupply(t, root, "AC", map[string]string{"type": "Mains", "online": "0"})
+	writeSupply(t, root, "BAT0", map[string]string{"type": "Battery", "capacity": "40", "status": "Discharging"})
+	writeSupply(t, root, "BAT1", map[string]string{"type": "Battery", "capacity": "60", "status": "Discharging"})
+	// 無線滑鼠的電池不列入計算
+	writeSupply(t, root, "hidpp_battery_0", map[string]string{"type": "Battery", "scope": "Device", "capacity": "5"})
+
+	status, err := NewPower(root, config.PowerConfig{}).Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.OnAC || !status.HasBattery || status.BatteryPercent != 50 {
+		t.Errorf("Expected battery power at 50%%, got %+v", status)
+	}
+}
+
+func TestPower_StatusDesktop(t *testing.T) {
+	status, err := NewPower(t.TempDir(), config.PowerConfig{}).Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if !status.OnAC || status.HasBattery {
+		t.Errorf("Expected a machine without battery to count as AC powered, got %+v", status)
+	}
+}
+
+func TestPower_Check(t *testing.T) {
+	tests := []struct {
+		name   string
+		online string
+		cfg    config.PowerConfig
+		want   bool
+	}{
+		{"ac only on AC", "1", config.PowerConfig{ACOnly: true}, true},
+		{"ac only on battery", "0", config.PowerConfig{ACOnly: true}, false},
+		{"battery above minimum", "0", config.PowerConfig{MinBatteryPercent: 20}, true},
+		{"battery below minimum", "0", config.PowerConfig{MinBatteryPercent: 50}, false},
+		{"AC ignores minimum", "1", config.PowerConfig{MinBatteryPercent: 50}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeSupply(t, root, "AC", map[string]string{"type": "Mains", "online": tt.online})
+			writeSupply(t, root, "BAT0", map[string]string{"type": "Battery", "capacity": "30", "status": "Discharging"})
+
+			ok, reason, err := NewPower(root, tt.cfg).Check()
+			if err != nil {
+				t.Fatalf("Check failed: %v", err)
+			}
+			if ok != tt.want {
+				t.Errorf("Expected ok=%v, got %v (%s)", tt.want, ok, reason)
+			}
+			if !ok && reason == "" {
+				t.Error("Expected a reason when prevention is not allowed")
+			}
+		})
+	}
+}
+
+func TestPower_MissingRoot(t *testing.T) {
+	if _, _, err := NewPower(filepath.Join(t.TempDir(), "missing"), config.PowerConfig{ACOnly: true}).Check(); err == nil {
+		t.Error("Expected error for a missing power_supply directory")
+	}
+}
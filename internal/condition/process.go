@@ -0,0 +1,149 @@
+package condition
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/HanksJCTsai/goidleguard/internal/config"
+)
+
+// DefaultProcRoot 為 Linux 上 procfs 的掛載點
+const DefaultProcRoot = "/proc"
+
+// NewProcesses 編譯程序規則；root 為空字串時使用 DefaultProcRoot。
+// 規則已由 ValidateConfig 驗證，這裡仍回傳編譯錯誤以免直接使用未驗證的設定。
+func NewProcesses(root string, rules []config.ProcessRule) (*Processes, error) {
+	if root == "" {
+		root = DefaultProcRoot
+	}
+	p := &Processes{Root: root}
+	for i, r := range rules {
+		rule, err := compileRule(r)
+		if err != nil {
+			return nil, fmt.Errorf("processes[%d]: %w", i, err)
+		}
+		if r.Exclude {
+			p.exclude = append(p.exclude, rule)
+		} else {
+			p.include = append(p.include, rule)
+		}
+	}
+	return p, nil
+}
+
+func compileRule(r config.ProcessRule) (processRule, error) {
+	var rule processRule
+	var err error
+	if r.Name != "" {
+		if rule.name, err = regexp.Compile(r.Name); err != nil {
+			return rule, fmt.Errorf("invalid name pattern: %w", err)
+		}
+	}
+	if r.Cmdline != "" {
+		if rule.cmdline, err = regexp.Compile(r.Cmdline); err != nil {
+			return rule, fmt.Errorf("invalid cmdline pattern: %w", err)
+		}
+	}
+	return rule, nil
+}
+
+// Enabled 回傳是否設定了任何 include 規則；沒有 include 規則時不限制程序
+func (p *Processes) Enabled() bool {
+	return len(p.include) > 0
+}
+
+// Match 回傳第一個符合任一 include 規則且不符合任何 exclude 規則的程序，沒有時回傳 nil
+func (p *Processes) Match() (*Process, error) {
+	procs, err := ListProcesses(p.Root)
+	if err != nil {
+		return nil, err
+	}
+	for i := range procs {
+		if matchAny(p.include, procs[i]) && !matchAny(p.exclude, procs[i]) {
+			return &procs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func matchAny(rules []processRule, proc Process) bool {
+	for _, r := range rules {
+		if r.matches(proc) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches 回傳程序是否同時符合規則中設定的所有項目
+func (r processRule) matches(proc Process) bool {
+	if r.name != nil && !r.name.MatchString(proc.Name) {
+		return false
+	}
+	if r.cmdline != nil && !r.cmdline.MatchString(proc.Cmdline) {
+		return false
+	}
+	return r.name != nil || r.cmdline != nil
+}
+
+// ListProcesses 列出 root (procfs) 下所有程序；讀取期間結束的程序會被略過
+func ListProcesses(root string) ([]Process, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("read %s failed: %w", root, err)
+	}
+	var procs []Process
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, e.Name())
+		comm, err := os.ReadFile(filepath.Join(dir, "comm"))
+		if err != nil {
+			continue
+		}
+		// kernel thread 的 cmdline 為空，讀取失敗時同樣視為空字串
+		cmdline, _ := os.ReadFile(filepath.Join(dir, "cmdline"))
+		procs = append(procs, Process{
+			PID:     pid,
+			Name:    strings.TrimSpace(string(comm)),
+			Cmdline: strings.TrimSpace(strings.ReplaceAll(string(cmdline), "\x00", " ")),
+		})
+	}
+	return procs, nil
+}
+
+// String 回傳程序的描述，例如 "make[1234]"
+func (p Process) String() string {
+	return fmt.Sprintf("%s[%d]", p.Name, p.PID)
+}
+
+// PIDStartTime 回傳 root (procfs) 下 pid 的啟動時間 (/proc/<pid>/stat 第 22 欄，開機後的 clock ticks)。
+// 程序不存在或已結束但尚未被回收 (zombie) 時 ok 為 false；pid 被其他程序重新使用時啟動時間會不同。
+func PIDStartTime(root string, pid int) (start uint64, ok bool) {
+	data, err := os.ReadFile(filepath.Join(root, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return 0, false
+	}
+	// stat 格式為 "pid (comm) state ... starttime ..."，comm 可能包含空白與括號，因此從最後一個 ')' 之後解析
+	stat := string(data)
+	i := strings.LastIndexByte(stat, ')')
+	if i < 0 {
+		return 0, false
+	}
+	// fields[0] 為第 3 欄 state，starttime 為第 22 欄
+	fields := strings.Fields(stat[i+1:])
+	if len(fields) < 20 || fields[0] == "Z" || fields[0] == "X" {
+		return 0, false
+	}
+	start, err = strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return start, true
+}
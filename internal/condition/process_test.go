@@ -0,0 +1,128 @@
+package condition
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/HanksJCTsai/goidleguard/internal/config"
+)
+
+// writeProc 在 root 下建立一個假的 /proc/<pid>
+func writeProc(t *testing.T, root string, pid int, comm string, args ...string) {
+	t.Helper()
+	dir := filepath.Join(root, strconv.Itoa(pid))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create %s: %v", dir, err)
+	}
+	var cmdline []byte
+	for _, a := range args {
+		cmdline = append(append(cmdline, a...), 0)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "comm"), []byte(comm+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write comm: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cmdline"), cmdline, 0644); err != nil {
+		t.Fatalf("Failed to write cmdline: %v", err)
+	}
+}
+
+func newFakeProc(t *testing.T) string {
+	root := t.TempDir()
+	writeProc(t, root, 1, "systemd", "/sbin/init")
+	writeProc(t, root, 42, "kthreadd")
+	writeProc(t, root, 1234, "make", "make", "-n", "all")
+	writeProc(t, root, 1300, "make", "make", "-j8", "all")
+	// 非數字的項目不是程序
+	if err := os.MkdirAll(filepath.Join(root, "sys"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestListProcesses(t *testing.T) {
+	procs, err := ListProcesses(newFakeProc(t))
+	if err != nil {
+		t.Fatalf("ListProcesses failed: %v", err)
+	}
+	if len(procs) != 4 {
+		t.Fatalf("Expected 4 processes, got %+v", procs)
+	}
+	for _, p := range procs {
+		if p.PID == 1234 && p.Cmdline != "make -n all" {
+			t.Errorf("Expected cmdline arguments to be joined with spaces, got %q", p.Cmdline)
+		}
+	}
+}
+
+func TestProcesses_Match(t *testing.T) {
+	root := newFakeProc(t)
+	tests := []struct {
+		name  string
+		rules []config.ProcessRule
+		want  int // 0 表示沒有符合的程序
+	}{
+		{"name", []config.ProcessRule{{Name: "^make$"}}, 1234},
+		{"cmdline", []config.ProcessRule{{Cmdline: "-j8"}}, 1300},
+		{"exclude", []config.ProcessRule{{Name: "^make$"}, {Cmdline: " -n ", Exclude: true}}, 1300},
+		{"name and cmdline", []config.ProcessRule{{Name: "^make$", Cmdline: "deploy"}}, 0},
+		{"no match", []config.ProcessRule{{Name: "^zoom$"}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewProcesses(root, tt.rules)
+			if err != nil {
+				t.Fatalf("NewProcesses failed: %v", err)
+			}
+			got, err := p.Match()
+			if err != nil {
+				t.Fatalf("Match failed: %v", err)
+			}
+			switch {
+			case tt.want == 0 && got != nil:
+				t.Errorf("Expected no match, got %s", got)
+			case tt.want != 0 && (got == nil || got.PID != tt.want):
+				t.Errorf("Expected pid %d, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestProcesses_Enabled(t *testing.T) {
+	p, _ := NewProcesses("", []config.ProcessRule{{Name: "x", Exclude: true}})
+	if p.Enabled() {
+		t.Error("Expected exclude-only rules not to restrict prevention")
+	}
+	if _, err := NewProcesses("", []config.ProcessRule{{Name: "("}}); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
+
+func TestPIDStartTime(t *testing.T) {
+	// state 之後到 starttime (第 22 欄) 前共 18 欄
+	const middle = "1 100 100 0 -1 4194560 100 0 0 0 1 2 0 0 20 0 1 0"
+	root := t.TempDir()
+	for pid, stat := range map[int]string{
+		100: "100 (sleep) S " + middle + " 5000 123456 789",
+		101: "101 (my (weird) job) R " + middle + " 6000 123456 789",
+		102: "102 (defunct) Z " + middle + " 7000 0 0",
+		104: "104 (truncated) S 1 104",
+	} {
+		dir := filepath.Join(root, strconv.Itoa(pid))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "stat"), []byte(stat+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := map[int]uint64{100: 5000, 101: 6000, 102: 0, 103: 0, 104: 0}
+	for pid, start := range want {
+		got, ok := PIDStartTime(root, pid)
+		if got != start || ok != (start != 0) {
+			t.Errorf("PIDStartTime(%d) = %d, %v; want %d", pid, got, ok, start)
+		}
+	}
+}
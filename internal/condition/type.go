@@ -0,0 +1,67 @@
+package condition
+
+import (
+	"regexp"
+	"time"
+)
+
+// PowerStatus 為目前的電源狀態
+type PowerStatus struct {
+	OnAC           bool // 接上外部電源 (或沒有電池的桌機)
+	HasBattery     bool
+	BatteryPercent int // 所有電池的平均電量，沒有電池時為 100
+}
+
+// Power 依 /sys/class/power_supply 判斷目前的電源是否允許防閒置
+type Power struct {
+	// Root 為 power_supply 的 sysfs 目錄，測試時可指向假的目錄樹
+	Root              string
+	ACOnly            bool
+	MinBatteryPercent int
+}
+
+// Process 為 /proc 中的一個程序
+type Process struct {
+	PID     int
+	Name    string // /proc/<pid>/comm
+	Cmdline string // /proc/<pid>/cmdline，參數以空白分隔
+}
+
+// processRule 為編譯後的程序規則，Name / Cmdline 為 nil 表示不檢查該項
+type processRule struct {
+	name    *regexp.Regexp
+	cmdline *regexp.Regexp
+}
+
+// Processes 依 /proc 中正在執行的程序判斷條件是否成立
+type Processes struct {
+	// Root 為 procfs 的掛載點，測試時可指向假的目錄樹
+	Root    string
+	include []processRule
+	exclude []processRule
+}
+
+// Load 依 /proc/loadavg 或 /proc/stat 判斷系統負載是否偏高
+type Load struct {
+	// Root 為 procfs 的掛載點，測試時可指向假的目錄樹
+	Root   string
+	Metric string
+	High   float64
+	Low    float64
+	Window time.Duration
+	// samples 為 cpu 指標在 Window 內的 /proc/stat 取樣，第一個為計算使用率的基準
+	samples []cpuSample
+}
+
+// cpuSample 為 /proc/stat 中 cpu 總計的一次取樣 (單位為 jiffies)
+type cpuSample struct {
+	at    time.Time
+	busy  uint64
+	total uint64
+}
+
+// Override 為暫時覆寫：到期前不論工作時段都持續防閒置。
+// 到期時間以 RFC 3339 寫在 Path 檔案中，因此 daemon 重新啟動後仍有效，CLI 也能透過同一個檔案設定
+type Override struct {
+	Path string
+}
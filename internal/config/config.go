@@ -3,9 +3,22 @@ package config
 import (
 	"fmt"
 	"os"
+	"regexp"
+	"strings"
 	"time"
 )
 
+// logindWhat 為 systemd-logind Inhibit 接受的 what 項目
+var logindWhat = map[string]bool{
+	"shutdown":             true,
+	"sleep":                true,
+	"idle":                 true,
+	"handle-power-key":     true,
+	"handle-suspend-key":   true,
+	"handle-hibernate-key": true,
+	"handle-lid-switch":    true,
+}
+
 // LoadConfig 讀取指定檔案（例如 config.yaml），並反序列化成 Config 結構。
 // 同時會呼叫 ValidateConfig 進行設定驗證。
 func LoadConfig(path string) (*APPConfig, error) {
@@ -62,14 +75,146 @@ func ValidateConfig(cfg *APPConfig) error {
 	// 驗證 IdlePrevention 的 Mode 值是否正確
 	if cfg.IdlePrevention.Mode != "key" &&
 		cfg.IdlePrevention.Mode != "mouse" &&
-		cfg.IdlePrevention.Mode != "mixed" {
+		cfg.IdlePrevention.Mode != "mixed" &&
+		cfg.IdlePrevention.Mode != "scroll" &&
+		cfg.IdlePrevention.Mode != "sequence" &&
+		cfg.IdlePrevention.Mode != "inhibit" {
 		return errInvalidMode
 	}
 
+	// 驗證 sequence 模式的每個步驟
+	if cfg.IdlePrevention.Mode == "sequence" && len(cfg.IdlePrevention.Sequence) == 0 {
+		return fmt.Errorf("idlePrevention.sequence must not be empty in sequence mode")
+	}
+	for i, step := range cfg.IdlePrevention.Sequence {
+		if err := validateSequenceStep(step); err != nil {
+			return fmt.Errorf("invalid idlePrevention.sequence[%d]: %w", i, err)
+		}
+	}
+
+	// 驗證 fallback chain 的策略名稱不可為空或重複 (名稱是否存在由 preventidle 在啟動時檢查)
+	if cfg.IdlePrevention.MaxFailures < 0 {
+		return fmt.Errorf("invalid idlePrevention.maxFailures must be >=0 (%d)", cfg.IdlePrevention.MaxFailures)
+	}
+	seen := make(map[string]bool)
+	for _, name := range cfg.IdlePrevention.Strategies {
+		if name == "" {
+			return fmt.Errorf("idlePrevention.strategies must not contain empty names")
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate idlePrevention.strategies entry (%s)", name)
+		}
+		seen[name] = true
+	}
+
+	// 驗證 key 模式的按鍵，會輸入文字的按鍵必須明確允許
+	if key := cfg.IdlePrevention.Key; key.Name != "" || len(key.Modifiers) > 0 {
+		if err := validateKey(key.Name, key.Modifiers, key.AllowText, key.AllowShortcut); err != nil {
+			return fmt.Errorf("invalid idlePrevention.key: %w", err)
+		}
+	}
+
+	// 驗證滑鼠移動模式與幅度
+	if mouse := cfg.IdlePrevention.Mouse; mouse.Pattern != "" && !MousePatterns[strings.ToLower(mouse.Pattern)] {
+		return fmt.Errorf("invalid idlePrevention.mouse.pattern (%s); must be one of: nudge, square, circle, random", mouse.Pattern)
+	}
+	if a := cfg.IdlePrevention.Mouse.Amplitude; a < 0 || a > maxMouseAmplitude {
+		return fmt.Errorf("invalid idlePrevention.mouse.amplitude must be between 0 and %d (%d)", maxMouseAmplitude, a)
+	}
+
+	// 驗證 timing model
+	timing := cfg.IdlePrevention.Timing
+	switch timing.Model {
+	case "", "fixed":
+	case "uniform":
+		if timing.Jitter <= 0 {
+			return fmt.Errorf("invalid idlePrevention.timing.jitter must be >0 for the uniform model (%s)", timing.Jitter)
+		}
+	case "poisson":
+		if timing.Mean <= 0 {
+			return fmt.Errorf("invalid idlePrevention.timing.mean must be >0 for the poisson model (%s)", timing.Mean)
+		}
+	default:
+		return fmt.Errorf("invalid idlePrevention.timing.model (%s); must be one of: fixed, uniform, poisson", timing.Model)
+	}
+	if timing.MinGap < 0 || timing.MaxGap < 0 {
+		return fmt.Errorf("idlePrevention.timing.minGap / maxGap must be >=0")
+	}
+	if timing.MaxGap > 0 {
+		if timing.MaxGap < cfg.IdlePrevention.Interval {
+			return fmt.Errorf("idlePrevention.timing.maxGap (%v) must be >= idlePrevention.interval (%v)", timing.MaxGap, cfg.IdlePrevention.Interval)
+		}
+		if timing.MaxGap < timing.MinGap {
+			return fmt.Errorf("idlePrevention.timing.maxGap (%v) must be >= minGap (%v)", timing.MaxGap, timing.MinGap)
+		}
+	}
+
+	// 驗證 logind inhibitor 的 what / mode
+	if logind := cfg.IdlePrevention.Logind; logind.Enabled {
+		if logind.What == "" {
+			return fmt.Errorf("idlePrevention.logind.what must not be empty")
+		}
+		for _, w := range strings.Split(logind.What, ":") {
+			if !logindWhat[w] {
+				return fmt.Errorf("invalid idlePrevention.logind.what item (%s)", w)
+			}
+		}
+		if logind.Mode != "block" && logind.Mode != "delay" {
+			return fmt.Errorf("invalid idlePrevention.logind.mode (%s); must be block or delay", logind.Mode)
+		}
+	}
+
+	// 驗證 absence timeout：小於閒置門檻時使用者稍微停下來就會被判定為離開
+	if timeout := cfg.IdlePrevention.AbsenceTimeout; timeout < 0 {
+		return fmt.Errorf("idlePrevention.absenceTimeout must be >=0")
+	} else if timeout > 0 && timeout < cfg.IdlePrevention.Interval {
+		return fmt.Errorf("idlePrevention.absenceTimeout (%v) must be >= idlePrevention.interval (%v)", timeout, cfg.IdlePrevention.Interval)
+	}
+
+	// 驗證 guard window：不能大於閒置門檻，否則達到門檻後仍會被判定為使用者剛有輸入而永遠無法送出
+	if window := cfg.IdlePrevention.GuardWindow; window < 0 {
+		return fmt.Errorf("idlePrevention.guardWindow must be >=0")
+	} else if window > cfg.IdlePrevention.Interval {
+		return fmt.Errorf("idlePrevention.guardWindow (%v) must be <= idlePrevention.interval (%v)", window, cfg.IdlePrevention.Interval)
+	}
+
+	// 驗證電源條件
+	if p := cfg.IdlePrevention.Power.MinBatteryPercent; p < 0 || p > 100 {
+		return fmt.Errorf("idlePrevention.power.minBatteryPercent (%d) must be between 0 and 100", p)
+	}
+
+	// 驗證程序規則
+	for i, rule := range cfg.IdlePrevention.Processes {
+		if err := validateProcessRule(rule); err != nil {
+			return fmt.Errorf("idlePrevention.processes[%d]: %w", i, err)
+		}
+	}
+	for i, rule := range cfg.IdlePrevention.PauseProcesses {
+		if err := validateProcessRule(rule); err != nil {
+			return fmt.Errorf("idlePrevention.pauseProcesses[%d]: %w", i, err)
+		}
+	}
+
+	// 驗證系統負載條件
+	if err := validateLoad(cfg.IdlePrevention.Load); err != nil {
+		return fmt.Errorf("idlePrevention.load: %w", err)
+	}
+
 	// 驗證 RetryPolicy 的 RetryInterval 格式
 	if _, err := time.ParseDuration(cfg.RetryPolicy.RetryInterval); err != nil {
 		return fmt.Errorf("invalid retryPolicy.retryInterval format (%s): %w", cfg.RetryPolicy.RetryInterval, err)
 	}
+	if cfg.RetryPolicy.MaxRetries < 0 {
+		return fmt.Errorf("invalid retryPolicy.maxRetries must be >=0 (%d)", cfg.RetryPolicy.MaxRetries)
+	}
+	if cfg.RetryPolicy.MaxInterval != "" {
+		if _, err := time.ParseDuration(cfg.RetryPolicy.MaxInterval); err != nil {
+			return fmt.Errorf("invalid retryPolicy.maxInterval format (%s): %w", cfg.RetryPolicy.MaxInterval, err)
+		}
+	}
+	if cfg.RetryPolicy.Jitter < 0 || cfg.RetryPolicy.Jitter > 1 {
+		return fmt.Errorf("invalid retryPolicy.jitter must be between 0 and 1 (%v)", cfg.RetryPolicy.Jitter)
+	}
 
 	// 驗證 WorkSchedule 每日的工作時段
 	for day, sessions := range cfg.WorkSchedule {
@@ -91,7 +236,106 @@ func ValidateConfig(cfg *APPConfig) error {
 	return nil
 }
 
-var errInvalidMode = &InvalidModeError{"Invalid idle prevention mode; must be one of: key, mouse, mixed"}
+// validateKey 驗證按鍵名稱與修飾鍵，空白名稱代表預設的 Shift。
+// 不在 shortcutSafeKeys 中的非文字按鍵 (例如 F5、Alt)，以及 ctrl / alt 搭配會觸發快捷鍵的按鍵
+// (例如 Alt+F4 關閉視窗) 必須明確允許
+func validateKey(name string, modifiers []string, allowText, allowShortcut bool) error {
+	text := false
+	if name != "" {
+		var ok bool
+		if text, ok = KeyNames[strings.ToLower(name)]; !ok {
+			return fmt.Errorf("unknown key name (%s)", name)
+		}
+		if missingKeys[goos][strings.ToLower(name)] {
+			return fmt.Errorf("key (%s) is not available on %s", name, goos)
+		}
+		if text && !allowText {
+			return fmt.Errorf("key (%s) types visible text; set allowText: true to use it anyway", name)
+		}
+	}
+	shortcut := false
+	for _, m := range modifiers {
+		if !ModifierNames[strings.ToLower(m)] {
+			return fmt.Errorf("invalid modifier (%s); must be shift, ctrl or alt", m)
+		}
+		if m := strings.ToLower(m); m == "ctrl" || m == "alt" {
+			shortcut = true
+		}
+	}
+	if name == "" || shortcutSafeKeys[strings.ToLower(name)] || allowShortcut {
+		return nil
+	}
+	if shortcut {
+		return fmt.Errorf("key (%s) with ctrl/alt is a shortcut that may close windows or trigger actions; set allowShortcut: true to use it anyway", name)
+	}
+	if !text {
+		return fmt.Errorf("key (%s) may trigger actions on its own (e.g. F1 opens help, F5 reloads, Alt focuses the menu bar); set allowShortcut: true to use it anyway", name)
+	}
+	return nil
+}
+
+// validateSequenceStep 驗證 sequence 的單一步驟
+func validateSequenceStep(step SequenceStep) error {
+	switch step.Action {
+	case "key":
+		if step.Key == "" {
+			return fmt.Errorf("key step requires a key name")
+		}
+		return validateKey(step.Key, step.Modifiers, step.AllowText, step.AllowShortcut)
+	case "move":
+		if step.DX == 0 && step.DY == 0 {
+			return fmt.Errorf("move step requires a non-zero dx or dy")
+		}
+		if step.DX < -maxSequenceMove || step.DX > maxSequenceMove || step.DY < -maxSequenceMove || step.DY > maxSequenceMove {
+			return fmt.Errorf("move step dx/dy must be between -%d and %d (%d, %d)", maxSequenceMove, maxSequenceMove, step.DX, step.DY)
+		}
+	case "scroll":
+		if step.Amount == 0 || step.Amount < -maxSequenceScroll || step.Amount > maxSequenceScroll {
+			return fmt.Errorf("scroll step amount must be non-zero and between -%d and %d (%d)", maxSequenceScroll, maxSequenceScroll, step.Amount)
+		}
+	case "wait":
+		if step.Ms <= 0 || step.Ms > maxSequenceWaitMs {
+			return fmt.Errorf("wait step ms must be between 1 and %d (%d)", maxSequenceWaitMs, step.Ms)
+		}
+	default:
+		return fmt.Errorf("unknown action (%s); must be one of: key, move, scroll, wait", step.Action)
+	}
+	return nil
+}
+
+// validateLoad 驗證負載指標與門檻：low 必須低於 high，cpu 使用率不超過 100%
+func validateLoad(load SystemLoadConfig) error {
+	switch load.Metric {
+	case "", "load1", "load5", "cpu":
+	default:
+		return fmt.Errorf("invalid metric %q (expected load1, load5 or cpu)", load.Metric)
+	}
+	if load.High < 0 || load.Low < 0 || load.Window < 0 {
+		return fmt.Errorf("high, low and window must not be negative")
+	}
+	if load.Low > 0 && load.Low >= load.High {
+		return fmt.Errorf("low (%v) must be lower than high (%v)", load.Low, load.High)
+	}
+	if load.Metric == "cpu" && load.High > 100 {
+		return fmt.Errorf("cpu high (%v) must not exceed 100", load.High)
+	}
+	return nil
+}
+
+// validateProcessRule 驗證程序規則至少設定一個可編譯的正規表示式
+func validateProcessRule(rule ProcessRule) error {
+	if rule.Name == "" && rule.Cmdline == "" {
+		return fmt.Errorf("rule requires a name or cmdline pattern")
+	}
+	for _, pattern := range []string{rule.Name, rule.Cmdline} {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid pattern (%s): %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+var errInvalidMode = &InvalidModeError{"Invalid idle prevention mode; must be one of: key, mouse, mixed, scroll, sequence, inhibit"}
 
 func (e *InvalidModeError) Error() string {
 	return e.Message
@@ -14,7 +14,7 @@ version:
   version: "1.0.0"
 
 scheduler:
-  interval: "10m"
+  interval: "1m"
 
 idlePrevention:
   enabled: true
@@ -221,13 +221,334 @@ func TestValidateConfig_InvalidIdlePreventionMode(t *testing.T) {
 	if err == nil {
 		t.Errorf("Expected error for invalid IdlePrevention.Mode, got nil")
 	} else {
-		expected := "Invalid idle prevention mode; must be one of: key, mouse, mixed"
+		expected := "Invalid idle prevention mode; must be one of: key, mouse, mixed, scroll, sequence, inhibit"
 		if err.Error() != expected {
 			t.Errorf("Expected error message '%s', got '%s'", expected, err.Error())
 		}
 	}
 }
 
+func TestValidateConfig_InhibitMode(t *testing.T) {
+	cfg := &APPConfig{
+		Scheduler: SchedulerConfig{
+			Interval: (1 * time.Minute),
+		},
+		IdlePrevention: IdlePreventionConfig{
+			Enabled:  true,
+			Interval: (5 * time.Minute),
+			Mode:     "inhibit",
+		},
+		RetryPolicy: RetryPolicyConfig{
+			MaxRetries:    3,
+			RetryInterval: "10s",
+		},
+	}
+
+	if err := ValidateConfig(cfg); err != nil {
+		t.Errorf("Expected inhibit mode to be valid, got error: %v", err)
+	}
+}
+
+func TestValidateConfig_Logind(t *testing.T) {
+	newCfg := func(logind LogindConfig) *APPConfig {
+		return &APPConfig{
+			Scheduler: SchedulerConfig{
+				Interval: (1 * time.Minute),
+			},
+			IdlePrevention: IdlePreventionConfig{
+				Enabled:  true,
+				Interval: (5 * time.Minute),
+				Mode:     "inhibit",
+				Logind:   logind,
+			},
+			RetryPolicy: RetryPolicyConfig{
+				MaxRetries:    3,
+				RetryInterval: "10s",
+			},
+		}
+	}
+
+	if err := ValidateConfig(newCfg(LogindConfig{Enabled: true, What: "idle:sleep", Why: "build", Mode: "block"})); err != nil {
+		t.Errorf("Expected valid logind config, got error: %v", err)
+	}
+	if err := ValidateConfig(newCfg(LogindConfig{Enabled: true, What: "idle:nap", Mode: "block"})); err == nil {
+		t.Errorf("Expected error for invalid logind what, got nil")
+	}
+	if err := ValidateConfig(newCfg(LogindConfig{Enabled: true, What: "sleep", Mode: "forever"})); err == nil {
+		t.Errorf("Expected error for invalid logind mode, got nil")
+	}
+	// 未啟用時不驗證
+	if err := ValidateConfig(newCfg(LogindConfig{What: "invalid"})); err != nil {
+		t.Errorf("Expected disabled logind config to be ignored, got error: %v", err)
+	}
+}
+
+func TestValidateConfig_Strategies(t *testing.T) {
+	newCfg := func(strategies []string, maxFailures int) *APPConfig {
+		return &APPConfig{
+			Scheduler: SchedulerConfig{
+				Interval: (1 * time.Minute),
+			},
+			IdlePrevention: IdlePreventionConfig{
+				Enabled:     true,
+				Interval:    (5 * time.Minute),
+				Mode:        "key",
+				Strategies:  strategies,
+				MaxFailures: maxFailures,
+			},
+			RetryPolicy: RetryPolicyConfig{
+				MaxRetries:    3,
+				RetryInterval: "10s",
+			},
+		}
+	}
+
+	if err := ValidateConfig(newCfg([]string{"inhibit", "uinput", "x11"}, 2)); err != nil {
+		t.Errorf("Expected valid strategies, got error: %v", err)
+	}
+	if err := ValidateConfig(newCfg([]string{"uinput", "uinput"}, 0)); err == nil {
+		t.Errorf("Expected error for duplicate strategies, got nil")
+	}
+	if err := ValidateConfig(newCfg([]string{""}, 0)); err == nil {
+		t.Errorf("Expected error for empty strategy name, got nil")
+	}
+	if err := ValidateConfig(newCfg(nil, -1)); err == nil {
+		t.Errorf("Expected error for negative maxFailures, got nil")
+	}
+}
+
+func TestValidateConfig_RetryPolicy(t *testing.T) {
+	newCfg := func(retry RetryPolicyConfig) *APPConfig {
+		return &APPConfig{
+			Scheduler: SchedulerConfig{
+				Interval: (1 * time.Minute),
+			},
+			IdlePrevention: IdlePreventionConfig{
+				Enabled:  true,
+				Interval: (5 * time.Minute),
+				Mode:     "key",
+			},
+			RetryPolicy: retry,
+		}
+	}
+
+	if err := ValidateConfig(newCfg(RetryPolicyConfig{MaxRetries: 3, RetryInterval: "1s", Backoff: true, MaxInterval: "30s", Jitter: 0.2})); err != nil {
+		t.Errorf("Expected valid retry policy, got error: %v", err)
+	}
+	if err := ValidateConfig(newCfg(RetryPolicyConfig{MaxRetries: -1, RetryInterval: "1s"})); err == nil {
+		t.Errorf("Expected error for negative maxRetries, got nil")
+	}
+	if err := ValidateConfig(newCfg(RetryPolicyConfig{RetryInterval: "1s", MaxInterval: "soon"})); err == nil {
+		t.Errorf("Expected error for invalid maxInterval, got nil")
+	}
+	if err := ValidateConfig(newCfg(RetryPolicyConfig{RetryInterval: "1s", Jitter: 1.5})); err == nil {
+		t.Errorf("Expected error for jitter out of range, got nil")
+	}
+}
+
+func TestValidateConfig_Key(t *testing.T) {
+	newCfg := func(key KeyConfig) *APPConfig {
+		return &APPConfig{
+			Scheduler: SchedulerConfig{
+				Interval: (1 * time.Minute),
+			},
+			IdlePrevention: IdlePreventionConfig{
+				Enabled:  true,
+				Interval: (5 * time.Minute),
+				Mode:     "key",
+				Key:      key,
+			},
+			RetryPolicy: RetryPolicyConfig{
+				MaxRetries:    3,
+				RetryInterval: "10s",
+			},
+		}
+	}
+
+	valid := []KeyConfig{
+		{},
+		{Name: "F15"},
+		{Name: "ScrollLock"},
+		{Name: "shift", Modifiers: []string{"Ctrl", "alt"}},
+		{Name: "Space", AllowText: true},
+		{Name: "F4", Modifiers: []string{"alt"}, AllowShortcut: true},
+		{Name: "F15", Modifiers: []string{"ctrl", "alt"}},
+		{Name: "ScrollLock", Modifiers: []string{"Ctrl"}},
+		{Name: "F5", AllowShortcut: true},
+		{Name: "Alt", AllowShortcut: true},
+		{Name: "F20"},
+	}
+	for _, key := range valid {
+		if err := ValidateConfig(newCfg(key)); err != nil {
+			t.Errorf("Expected key %+v to be valid, got error: %v", key, err)
+		}
+	}
+
+	invalid := []KeyConfig{
+		{Name: "Space"},
+		{Name: "a"},
+		{Name: "Hyper"},
+		{Name: "F15", Modifiers: []string{"super"}},
+		// 會關閉視窗的快捷鍵需要 allowShortcut
+		{Name: "F4", Modifiers: []string{"alt"}},
+		{Name: "w", Modifiers: []string{"ctrl"}, AllowText: true},
+		{Name: "F5", Modifiers: []string{"Ctrl"}},
+		{Name: "Pause", Modifiers: []string{"ctrl"}},
+		// 單獨按下也有副作用的按鍵同樣需要 allowShortcut
+		{Name: "F5"},
+		{Name: "F1"},
+		{Name: "Alt"},
+		{Name: "Pause"},
+	}
+	for _, key := range invalid {
+		if err := ValidateConfig(newCfg(key)); err == nil {
+			t.Errorf("Expected error for key %+v, got nil", key)
+		}
+	}
+}
+
+func TestValidateKey_MissingOnPlatform(t *testing.T) {
+	defer func(orig string) { goos = orig }(goos)
+
+	goos = "darwin"
+	if err := validateKey("F21", nil, false, false); err == nil {
+		t.Error("Expected F21 to be rejected on darwin, got nil")
+	}
+	if err := validateKey("F20", nil, false, false); err != nil {
+		t.Errorf("Expected F20 to be valid on darwin, got error: %v", err)
+	}
+	goos = "linux"
+	if err := validateKey("F21", nil, false, false); err != nil {
+		t.Errorf("Expected F21 to be valid on linux, got error: %v", err)
+	}
+}
+
+func TestValidateConfig_Mouse(t *testing.T) {
+	newCfg := func(mouse MouseConfig) *APPConfig {
+		return &APPConfig{
+			Scheduler: SchedulerConfig{
+				Interval: (1 * time.Minute),
+			},
+			IdlePrevention: IdlePreventionConfig{
+				Enabled:  true,
+				Interval: (5 * time.Minute),
+				Mode:     "mouse",
+				Mouse:    mouse,
+			},
+			RetryPolicy: RetryPolicyConfig{
+				MaxRetries:    3,
+				RetryInterval: "10s",
+			},
+		}
+	}
+
+	for _, mouse := range []MouseConfig{{}, {Pattern: "Circle", Amplitude: 10}, {Pattern: "random", Amplitude: 50}} {
+		if err := ValidateConfig(newCfg(mouse)); err != nil {
+			t.Errorf("Expected mouse %+v to be valid, got error: %v", mouse, err)
+		}
+	}
+	for _, mouse := range []MouseConfig{{Pattern: "zigzag"}, {Pattern: "square", Amplitude: -1}, {Amplitude: 500}} {
+		if err := ValidateConfig(newCfg(mouse)); err == nil {
+			t.Errorf("Expected error for mouse %+v, got nil", mouse)
+		}
+	}
+}
+
+func TestValidateConfig_Timing(t *testing.T) {
+	newCfg := func(timing TimingConfig) *APPConfig {
+		return &APPConfig{
+			Scheduler: SchedulerConfig{
+				Interval: (1 * time.Minute),
+			},
+			IdlePrevention: IdlePreventionConfig{
+				Enabled:  true,
+				Interval: (5 * time.Minute),
+				Mode:     "key",
+				Timing:   timing,
+			},
+			RetryPolicy: RetryPolicyConfig{
+				MaxRetries:    3,
+				RetryInterval: "10s",
+			},
+		}
+	}
+
+	valid := []TimingConfig{
+		{},
+		{Model: "fixed"},
+		{Model: "uniform", Jitter: time.Minute, MaxGap: 6 * time.Minute},
+		{Model: "poisson", Mean: 30 * time.Second, MinGap: 5 * time.Minute, MaxGap: 8 * time.Minute},
+	}
+	for _, timing := range valid {
+		if err := ValidateConfig(newCfg(timing)); err != nil {
+			t.Errorf("Expected timing %+v to be valid, got error: %v", timing, err)
+		}
+	}
+
+	invalid := []TimingConfig{
+		{Model: "gaussian"},
+		{Model: "uniform"},
+		{Model: "poisson"},
+		{MaxGap: time.Minute},
+		{MinGap: 7 * time.Minute, MaxGap: 6 * time.Minute},
+		{MinGap: -time.Second},
+	}
+	for _, timing := range invalid {
+		if err := ValidateConfig(newCfg(timing)); err == nil {
+			t.Errorf("Expected error for timing %+v, got nil", timing)
+		}
+	}
+}
+
+func TestValidateConfig_Sequence(t *testing.T) {
+	newCfg := func(steps ...SequenceStep) *APPConfig {
+		return &APPConfig{
+			Scheduler: SchedulerConfig{
+				Interval: (1 * time.Minute),
+			},
+			IdlePrevention: IdlePreventionConfig{
+				Enabled:  true,
+				Interval: (5 * time.Minute),
+				Mode:     "sequence",
+				Sequence: steps,
+			},
+			RetryPolicy: RetryPolicyConfig{
+				MaxRetries:    3,
+				RetryInterval: "10s",
+			},
+		}
+	}
+
+	valid := newCfg(
+		SequenceStep{Action: "key", Key: "F15", Modifiers: []string{"ctrl"}},
+		SequenceStep{Action: "move", DX: 5, DY: -5},
+		SequenceStep{Action: "wait", Ms: 200},
+		SequenceStep{Action: "scroll", Amount: -1},
+	)
+	if err := ValidateConfig(valid); err != nil {
+		t.Errorf("Expected valid sequence, got error: %v", err)
+	}
+
+	if err := ValidateConfig(newCfg()); err == nil {
+		t.Errorf("Expected error for empty sequence, got nil")
+	}
+	invalid := []SequenceStep{
+		{Action: "jump"},
+		{Action: "key"},
+		{Action: "key", Key: "a"},
+		{Action: "key", Key: "F4", Modifiers: []string{"alt"}},
+		{Action: "move"},
+		{Action: "move", DX: 1000},
+		{Action: "scroll"},
+		{Action: "wait", Ms: 0},
+	}
+	for _, step := range invalid {
+		if err := ValidateConfig(newCfg(step)); err == nil {
+			t.Errorf("Expected error for step %+v, got nil", step)
+		}
+	}
+}
+
 func TestValidateConfig_InvalidRetryInterval(t *testing.T) {
 	cfg := &APPConfig{
 		Version: VersionConfig{
@@ -382,3 +703,161 @@ func TestTimeParsingForWorkSchedule(t *testing.T) {
 		t.Errorf("Failed to parse end time: %v", err)
 	}
 }
+
+func TestValidateConfig_AbsenceTimeout(t *testing.T) {
+	newCfg := func(timeout time.Duration) *APPConfig {
+		return &APPConfig{
+			Scheduler: SchedulerConfig{
+				Interval: (1 * time.Minute),
+			},
+			IdlePrevention: IdlePreventionConfig{
+				Enabled:        true,
+				Interval:       (5 * time.Minute),
+				Mode:           "key",
+				AbsenceTimeout: timeout,
+			},
+			RetryPolicy: RetryPolicyConfig{
+				MaxRetries:    3,
+				RetryInterval: "10s",
+			},
+		}
+	}
+
+	for _, timeout := range []time.Duration{0, 5 * time.Minute, time.Hour} {
+		if err := ValidateConfig(newCfg(timeout)); err != nil {
+			t.Errorf("Expected absenceTimeout %v to be valid, got error: %v", timeout, err)
+		}
+	}
+	for _, timeout := range []time.Duration{-time.Second, time.Minute} {
+		if err := ValidateConfig(newCfg(timeout)); err == nil {
+			t.Errorf("Expected absenceTimeout %v to be invalid", timeout)
+		}
+	}
+}
+
+func TestValidateConfig_GuardWindow(t *testing.T) {
+	newCfg := func(window time.Duration) *APPConfig {
+		return &APPConfig{
+			Scheduler: SchedulerConfig{
+				Interval: (1 * time.Minute),
+			},
+			IdlePrevention: IdlePreventionConfig{
+				Enabled:     true,
+				Interval:    (5 * time.Minute),
+				Mode:        "key",
+				GuardWindow: window,
+			},
+			RetryPolicy: RetryPolicyConfig{
+				MaxRetries:    3,
+				RetryInterval: "10s",
+			},
+		}
+	}
+
+	for _, window := range []time.Duration{0, 2 * time.Second, 5 * time.Minute} {
+		if err := ValidateConfig(newCfg(window)); err != nil {
+			t.Errorf("Expected guardWindow %v to be valid, got error: %v", window, err)
+		}
+	}
+	for _, window := range []time.Duration{-time.Second, 6 * time.Minute} {
+		if err := ValidateConfig(newCfg(window)); err == nil {
+			t.Errorf("Expected guardWindow %v to be invalid", window)
+		}
+	}
+}
+
+func TestValidateConfig_Processes(t *testing.T) {
+	newCfg := func(rules ...ProcessRule) *APPConfig {
+		return &APPConfig{
+			Scheduler: SchedulerConfig{
+				Interval: (1 * time.Minute),
+			},
+			IdlePrevention: IdlePreventionConfig{
+				Enabled:   true,
+				Interval:  (5 * time.Minute),
+				Mode:      "key",
+				Processes: rules,
+			},
+			RetryPolicy: RetryPolicyConfig{
+				MaxRetries:    3,
+				RetryInterval: "10s",
+			},
+		}
+	}
+
+	if err := ValidateConfig(newCfg(ProcessRule{Name: "^zoom$"}, ProcessRule{Cmdline: "make .*-n", Exclude: true})); err != nil {
+		t.Errorf("Expected process rules to be valid, got error: %v", err)
+	}
+	for _, rule := range []ProcessRule{{}, {Name: "("}, {Cmdline: "[a-"}, {Exclude: true}} {
+		if err := ValidateConfig(newCfg(rule)); err == nil {
+			t.Errorf("Expected process rule %+v to be invalid", rule)
+		}
+	}
+}
+
+func TestValidateConfig_PauseProcesses(t *testing.T) {
+	cfg := &APPConfig{
+		Scheduler: SchedulerConfig{
+			Interval: (1 * time.Minute),
+		},
+		IdlePrevention: IdlePreventionConfig{
+			Enabled:        true,
+			Interval:       (5 * time.Minute),
+			Mode:           "key",
+			PauseProcesses: []ProcessRule{{Name: "^obs$"}},
+		},
+		RetryPolicy: RetryPolicyConfig{
+			MaxRetries:    3,
+			RetryInterval: "10s",
+		},
+	}
+	if err := ValidateConfig(cfg); err != nil {
+		t.Errorf("Expected pause list to be valid, got error: %v", err)
+	}
+	cfg.IdlePrevention.PauseProcesses = append(cfg.IdlePrevention.PauseProcesses, ProcessRule{Cmdline: "("})
+	if err := ValidateConfig(cfg); err == nil {
+		t.Error("Expected invalid pause pattern to be rejected")
+	}
+}
+
+func TestValidateConfig_Load(t *testing.T) {
+	newCfg := func(load SystemLoadConfig) *APPConfig {
+		return &APPConfig{
+			Scheduler: SchedulerConfig{
+				Interval: (1 * time.Minute),
+			},
+			IdlePrevention: IdlePreventionConfig{
+				Enabled:  true,
+				Interval: (5 * time.Minute),
+				Mode:     "key",
+				Load:     load,
+			},
+			RetryPolicy: RetryPolicyConfig{
+				MaxRetries:    3,
+				RetryInterval: "10s",
+			},
+		}
+	}
+
+	for _, load := range []SystemLoadConfig{
+		{},
+		{High: 3},
+		{Metric: "load5", High: 4, Low: 2},
+		{Metric: "cpu", High: 70, Low: 40, Window: 2 * time.Minute},
+	} {
+		if err := ValidateConfig(newCfg(load)); err != nil {
+			t.Errorf("Expected load %+v to be valid, got error: %v", load, err)
+		}
+	}
+	for _, load := range []SystemLoadConfig{
+		{Metric: "iowait", High: 3},
+		{High: -1},
+		{High: 3, Low: 3},
+		{Metric: "cpu", High: 150},
+		{Metric: "cpu", High: 70, Window: -time.Second},
+	} {
+		if err := ValidateConfig(newCfg(load)); err == nil {
+			t.Errorf("Expected load %+v to be invalid", load)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// KeyNames 為 idlePrevention.key.name 可使用的可攜式按鍵名稱 (小寫)，
+// 值為 true 表示該鍵會在焦點視窗輸入可見文字 (或換行、縮排)，需要 allowText 才能使用。
+var KeyNames = map[string]bool{
+	"shift":      false,
+	"ctrl":       false,
+	"alt":        false,
+	"scrolllock": false,
+	"pause":      false,
+	"space":      true,
+	"tab":        true,
+	"enter":      true,
+}
+
+// MousePatterns 為 idlePrevention.mouse.pattern 可使用的移動模式
+var MousePatterns = map[string]bool{
+	"nudge":  true,
+	"square": true,
+	"circle": true,
+	"random": true,
+}
+
+const (
+	// maxMouseAmplitude 為滑鼠移動幅度的上限，避免游標大幅移動干擾使用者
+	maxMouseAmplitude = 50
+	// maxSequenceMove / maxSequenceScroll / maxSequenceWaitMs 為 sequence 單一步驟的上限
+	maxSequenceMove   = 100
+	maxSequenceScroll = 10
+	maxSequenceWaitMs = 60000
+)
+
+// shortcutSafeKeys 為單獨按下或與 ctrl / alt 同時按下都不會觸發動作的按鍵 (F13~F24 於 init 中加入)。
+// 其餘非文字按鍵單獨按下也可能有副作用 (F1 開啟說明、F5 重新整理、Windows 上 Alt 會移到選單列)，
+// 搭配 ctrl / alt 時 (例如 Alt+F4、Ctrl+W、Ctrl+Pause) 則會觸發快捷鍵，都需要 allowShortcut
+var shortcutSafeKeys = map[string]bool{
+	"shift":      true,
+	"ctrl":       true,
+	"scrolllock": true,
+}
+
+// missingKeys 為各平台沒有對應按鍵代碼的按鍵名稱：macOS 沒有 F21~F24
+var missingKeys = map[string]map[string]bool{
+	"darwin": {"f21": true, "f22": true, "f23": true, "f24": true},
+}
+
+// goos 為驗證按鍵時使用的平台，測試時可替換
+var goos = runtime.GOOS
+
+// ModifierNames 為 idlePrevention.key.modifiers 可使用的修飾鍵名稱
+var ModifierNames = map[string]bool{
+	"shift": true,
+	"ctrl":  true,
+	"alt":   true,
+}
+
+func init() {
+	for i := 1; i <= 24; i++ {
+		KeyNames[fmt.Sprintf("f%d", i)] = false
+		if i >= 13 {
+			shortcutSafeKeys[fmt.Sprintf("f%d", i)] = true
+		}
+	}
+	for c := 'a'; c <= 'z'; c++ {
+		KeyNames[string(c)] = true
+	}
+	for c := '0'; c <= '9'; c++ {
+		KeyNames[string(c)] = true
+	}
+}
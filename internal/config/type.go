@@ -18,9 +18,101 @@ type VersionConfig struct {
 }
 
 type IdlePreventionConfig struct {
-	Enabled  bool          `yaml:"enabled" json:"enabled"`
-	Interval time.Duration `yaml:"interval" json:"interval"` // 例如 "5m"
-	Mode     string        `yaml:"mode" json:"mode"`         // 可選值： "key"、"mouse"、"mixed"
+	Enabled     bool           `yaml:"enabled" json:"enabled"`
+	Interval    time.Duration  `yaml:"interval" json:"interval"`       // 例如 "5m"
+	Mode        string         `yaml:"mode" json:"mode"`               // 可選值： "key"、"mouse"、"mixed"、"scroll" (滾輪上下各一格)、"sequence" (依 Sequence 執行)、"inhibit" (僅持有電源宣告，不送出任何輸入)
+	Backend     string         `yaml:"backend" json:"backend"`         // preventidle 的 backend 名稱，空字串為 "native"
+	Strategies  []string       `yaml:"strategies" json:"strategies"`   // 依優先順序的 fallback chain，例如 inhibit → uinput → x11；空值時依 Mode 使用 Backend
+	MaxFailures int            `yaml:"maxFailures" json:"maxFailures"` // 策略連續失敗幾次後降級，0 為預設值 3
+	Key         KeyConfig      `yaml:"key" json:"key"`
+	Mouse       MouseConfig    `yaml:"mouse" json:"mouse"`
+	Timing      TimingConfig   `yaml:"timing" json:"timing"`
+	Sequence    []SequenceStep `yaml:"sequence" json:"sequence"` // sequence 模式依序執行的步驟
+	Logind      LogindConfig   `yaml:"logind" json:"logind"`
+	// AbsenceTimeout 為工作時段中使用者多久沒有真正的輸入就暫停防閒置，直到使用者回來；0 表示停用
+	AbsenceTimeout time.Duration `yaml:"absenceTimeout" json:"absenceTimeout"`
+	// GuardWindow 為每次送出模擬輸入前的檢查：使用者在此時間內有真正的輸入就取消剩餘步驟；0 表示停用
+	GuardWindow time.Duration `yaml:"guardWindow" json:"guardWindow"`
+	Session     SessionConfig `yaml:"session" json:"session"`
+	Power       PowerConfig   `yaml:"power" json:"power"`
+	// Processes 為程序規則 (僅 Linux)：設定 include 規則時，只有符合的程序正在執行才防閒置
+	Processes []ProcessRule `yaml:"processes" json:"processes"`
+	// PauseProcesses 為排除清單 (僅 Linux)：任一符合的程序正在執行時強制暫停防閒置，不論工作時段
+	PauseProcesses []ProcessRule `yaml:"pauseProcesses" json:"pauseProcesses"`
+	// Load 為系統負載條件 (僅 Linux)：負載偏高時不論工作時段都防閒置，例如工作時段外的長時間編譯
+	Load SystemLoadConfig `yaml:"load" json:"load"`
+}
+
+// KeyConfig 定義 key / mixed 模式送出的按鍵
+type KeyConfig struct {
+	Name      string   `yaml:"name" json:"name"`           // 可攜式按鍵名稱，例如 "F15"、"Shift"、"ScrollLock"，空字串為 Shift
+	Modifiers []string `yaml:"modifiers" json:"modifiers"` // 同時按住的修飾鍵：shift、ctrl、alt
+	AllowText bool     `yaml:"allowText" json:"allowText"` // 允許使用會輸入可見文字的按鍵 (例如 Space、A)
+	// AllowShortcut 允許 ctrl / alt 搭配可能關閉視窗或觸發動作的按鍵 (例如 Alt+F4、Ctrl+W)
+	AllowShortcut bool `yaml:"allowShortcut" json:"allowShortcut"`
+}
+
+// MouseConfig 定義 mouse / mixed 模式的移動方式，所有模式最後都會回到起點
+type MouseConfig struct {
+	Pattern   string `yaml:"pattern" json:"pattern"`     // nudge (移動後返回)、square、circle、random (範圍內隨機移動)，空字串為 nudge
+	Amplitude int    `yaml:"amplitude" json:"amplitude"` // 移動幅度 (像素)，0 為 1
+}
+
+// SequenceStep 為 sequence 模式的一個步驟，依 Action 使用對應欄位
+type SequenceStep struct {
+	Action    string   `yaml:"action" json:"action"`       // key、move、scroll、wait
+	Key       string   `yaml:"key" json:"key"`             // key：可攜式按鍵名稱
+	Modifiers []string `yaml:"modifiers" json:"modifiers"` // key：同時按住的修飾鍵
+	AllowText bool     `yaml:"allowText" json:"allowText"` // key：允許會輸入可見文字的按鍵
+	DX        int      `yaml:"dx" json:"dx"`               // move：水平位移 (像素)
+	DY        int      `yaml:"dy" json:"dy"`               // move：垂直位移 (像素)
+	Amount    int      `yaml:"amount" json:"amount"`       // scroll：滾輪格數，正數向上、負數向下
+	Ms        int      `yaml:"ms" json:"ms"`               // wait：等待毫秒數
+	// AllowShortcut 為 key 步驟允許 ctrl / alt 搭配可能觸發動作的按鍵 (同 KeyConfig.AllowShortcut)
+	AllowShortcut bool `yaml:"allowShortcut" json:"allowShortcut"`
+}
+
+// TimingConfig 定義達到閒置門檻後何時送出模擬輸入，避免完全週期性的輸入
+type TimingConfig struct {
+	Model  string        `yaml:"model" json:"model"`   // fixed (達到門檻立即送出)、uniform、poisson，空字串為 fixed
+	Jitter time.Duration `yaml:"jitter" json:"jitter"` // uniform：門檻後再隨機延後 0~jitter
+	Mean   time.Duration `yaml:"mean" json:"mean"`     // poisson：門檻後的平均延遲 (指數分布)
+	MinGap time.Duration `yaml:"minGap" json:"minGap"` // 兩次輸入間隔 (自最後一次輸入起算) 的下限，0 表示不限制
+	MaxGap time.Duration `yaml:"maxGap" json:"maxGap"` // 兩次輸入間隔的上限，0 表示不限制
+}
+
+// LogindConfig 定義工作時段中持有的 systemd-logind inhibitor lock (僅 Linux)
+type LogindConfig struct {
+	Enabled bool   `yaml:"enabled" json:"enabled"`
+	What    string `yaml:"what" json:"what"` // 以 ":" 分隔，例如 "idle:sleep"
+	Why     string `yaml:"why" json:"why"`   // 顯示在 systemd-inhibit --list 的原因
+	Mode    string `yaml:"mode" json:"mode"` // "block" 或 "delay"
+}
+
+// SessionConfig 定義如何依登入 session 的狀態調整防閒置 (僅 Linux，透過 systemd-logind)
+type SessionConfig struct {
+	RespectLock bool `yaml:"respectLock" json:"respectLock"` // session 鎖定 (LockedHint) 時不送出模擬輸入，解鎖後自動恢復
+}
+
+// PowerConfig 定義允許防閒置的電源條件 (僅 Linux，讀取 /sys/class/power_supply)
+type PowerConfig struct {
+	ACOnly            bool `yaml:"acOnly" json:"acOnly"`                       // 只在接上外部電源時防閒置
+	MinBatteryPercent int  `yaml:"minBatteryPercent" json:"minBatteryPercent"` // 使用電池時電量低於此百分比就暫停，0 表示不限制
+}
+
+// ProcessRule 以正規表示式比對 /proc 中的程序，Name 與 Cmdline 同時設定時兩者都要符合
+type ProcessRule struct {
+	Name    string `yaml:"name" json:"name"`       // 比對 /proc/<pid>/comm，例如 "^zoom$"
+	Cmdline string `yaml:"cmdline" json:"cmdline"` // 比對完整命令列 (參數以空白分隔)
+	Exclude bool   `yaml:"exclude" json:"exclude"` // 符合此規則的程序不算數，用來排除 include 規則中不要的程序
+}
+
+// SystemLoadConfig 定義啟動防閒置的系統負載門檻，以 High / Low 兩個門檻避免在門檻附近反覆切換
+type SystemLoadConfig struct {
+	Metric string        `yaml:"metric" json:"metric"` // load1、load5 (/proc/loadavg 的 1 / 5 分鐘平均)、cpu (/proc/stat 的 CPU 使用率 %)，空字串為 load1
+	High   float64       `yaml:"high" json:"high"`     // 達到此值時啟動防閒置，0 表示停用
+	Low    float64       `yaml:"low" json:"low"`       // 低於此值才解除，0 為 high 的 80%
+	Window time.Duration `yaml:"window" json:"window"` // cpu：計算使用率的時間範圍，0 為 1m
 }
 
 type SchedulerConfig struct {
@@ -33,8 +125,11 @@ type LoggingConfig struct {
 }
 
 type RetryPolicyConfig struct {
-	MaxRetries    int    `yaml:"maxRetries" json:"maxRetries"`
-	RetryInterval string `yaml:"retryInterval" json:"retryInterval"` // 例如 "10s"
+	MaxRetries    int     `yaml:"maxRetries" json:"maxRetries"`
+	RetryInterval string  `yaml:"retryInterval" json:"retryInterval"` // 例如 "10s"
+	Backoff       bool    `yaml:"backoff" json:"backoff"`             // 每次重試後等待時間加倍
+	MaxInterval   string  `yaml:"maxInterval" json:"maxInterval"`     // 指數退避的等待上限，空字串表示不限制
+	Jitter        float64 `yaml:"jitter" json:"jitter"`               // 等待時間的隨機變動比例，0~1
 }
 
 type InvalidModeError struct {
@@ -0,0 +1,121 @@
+package preventidle
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// NativeBackend 為各平台預設的 backend 名稱，對應 GetIdleTime / CallSendInput / PreventSleep / AllowIdle
+const NativeBackend = "native"
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]BackendFactory)
+)
+
+func init() {
+	RegisterBackend(NativeBackend, func() (*Backend, error) {
+		return &Backend{
+			Name:      NativeBackend,
+			Idle:      IdleFunc(GetIdleTime),
+			Input:     InputFunc(CallSendInput),
+			Inhibitor: InhibitorFuncs{InhibitFunc: PreventSleep, ReleaseFunc: AllowIdle},
+		}, nil
+	})
+	RegisterBackend(DryRunBackendName, func() (*Backend, error) {
+		return NewDryRunBackend().Backend(), nil
+	})
+}
+
+// RegisterBackend 以名稱註冊 backend，名稱重複時 panic
+func RegisterBackend(name string, factory BackendFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic("preventidle: RegisterBackend factory is nil")
+	}
+	if _, dup := registry[name]; dup {
+		panic("preventidle: RegisterBackend called twice for backend " + name)
+	}
+	registry[name] = factory
+}
+
+// BackendNames 回傳所有已註冊的 backend 名稱 (已排序)
+func BackendNames() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewBackend 依名稱建立 backend，空字串代表 NativeBackend
+func NewBackend(name string) (*Backend, error) {
+	if name == "" {
+		name = NativeBackend
+	}
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown idle prevention backend %q (available: %v)", name, BackendNames())
+	}
+	return factory()
+}
+
+// OpenBackend 依名稱建立 backend，並以 NativeBackend 補上該 backend 未提供的元件
+func OpenBackend(name string) (*Backend, error) {
+	b, err := NewBackend(name)
+	if err != nil || b.Name == NativeBackend {
+		return b, err
+	}
+	native, err := NewBackend(NativeBackend)
+	if err != nil {
+		return nil, err
+	}
+	if b.Idle == nil {
+		b.Idle = native.Idle
+	}
+	if b.Input == nil {
+		b.Input = native.Input
+	}
+	if b.Inhibitor == nil {
+		b.Inhibitor = native.Inhibitor
+	}
+	return b, nil
+}
+
+// IdleFunc 讓一般函式滿足 IdleSource
+type IdleFunc func() (time.Duration, error)
+
+func (f IdleFunc) IdleTime() (time.Duration, error) {
+	return f()
+}
+
+// InputFunc 讓一般函式滿足 InputInjector
+type InputFunc func(in Input) error
+
+func (f InputFunc) SendInput(in Input) error {
+	return f(in)
+}
+
+// InhibitorFuncs 讓一組函式滿足 PowerInhibitor
+type InhibitorFuncs struct {
+	InhibitFunc func() error
+	ReleaseFunc func() error
+}
+
+func (f InhibitorFuncs) Inhibit() error {
+	return f.InhibitFunc()
+}
+
+func (f InhibitorFuncs) Release() error {
+	return f.ReleaseFunc()
+}
@@ -0,0 +1,30 @@
+//go:build linux
+// +build linux
+
+package preventidle
+
+func init() {
+	x11 := func() (*Backend, error) {
+		return &Backend{
+			Name:      "x11",
+			Idle:      IdleFunc(x11GetIdleTime),
+			Input:     InputFunc(x11SendInput),
+			Inhibitor: InhibitorFuncs{InhibitFunc: x11PreventSleep, ReleaseFunc: x11AllowIdle},
+		}, nil
+	}
+	RegisterBackend("x11", x11)
+	// xtest 為 x11 的別名，輸入經由 XTest extension 送出
+	RegisterBackend("xtest", x11)
+	RegisterBackend("uinput", func() (*Backend, error) {
+		return &Backend{
+			Name:  "uinput",
+			Input: InputFunc(uinputSendInput),
+		}, nil
+	})
+	RegisterBackend("dbus", func() (*Backend, error) {
+		return &Backend{
+			Name:      "dbus",
+			Inhibitor: newDBusInhibitor(""),
+		}, nil
+	})
+}
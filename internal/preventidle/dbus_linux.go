@@ -0,0 +1,200 @@
+//go:build linux
+// +build linux
+
+package preventidle
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/HanksJCTsai/goidleguard/pkg/logger"
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	dbusAppName       = "GoIdleGuard"
+	dbusInhibitReason = "GoIdleGuard work session"
+
+	// GNOME SessionManager 的 inhibit flags：4 = 暫停 (suspend)，8 = 閒置 (idle)
+	gnomeInhibitSuspend = 4
+	gnomeInhibitIdle    = 8
+
+	dbusReconnectMin = time.Second
+	dbusReconnectMax = 30 * time.Second
+)
+
+// dbusInhibitService 描述一個提供 Inhibit / UnInhibit 的 session bus 服務
+type dbusInhibitService struct {
+	dest      string
+	path      dbus.ObjectPath
+	inhibit   string
+	uninhibit string
+	args      func(app, reason string) []interface{}
+}
+
+// dbusInhibitServices 依序嘗試：freedesktop 標準介面 (KDE、Xfce 等)，再退回 GNOME 專屬介面
+var dbusInhibitServices = []dbusInhibitService{
+	{
+		dest:      "org.freedesktop.ScreenSaver",
+		path:      "/org/freedesktop/ScreenSaver",
+		inhibit:   "org.freedesktop.ScreenSaver.Inhibit",
+		uninhibit: "org.freedesktop.ScreenSaver.UnInhibit",
+		args: func(app, reason string) []interface{} {
+			return []interface{}{app, reason}
+		},
+	},
+	{
+		dest:      "org.gnome.SessionManager",
+		path:      "/org/gnome/SessionManager",
+		inhibit:   "org.gnome.SessionManager.Inhibit",
+		uninhibit: "org.gnome.SessionManager.Uninhibit",
+		args: func(app, reason string) []interface{} {
+			return []interface{}{app, uint32(0), reason, uint32(gnomeInhibitIdle | gnomeInhibitSuspend)}
+		},
+	},
+}
+
+// dbusInhibitor 透過 session bus 持有 screensaver inhibit cookie。
+// inhibit 會在呼叫端的 bus 連線中斷時被服務端自動解除，因此連線中斷後會自動重新連線並重新取得 cookie。
+type dbusInhibitor struct {
+	// address 為空字串時使用 DBUS_SESSION_BUS_ADDRESS 指定的 session bus
+	address string
+
+	mu      sync.Mutex
+	conn    *dbus.Conn
+	service *dbusInhibitService
+	cookie  uint32
+	stop    chan struct{}
+}
+
+func newDBusInhibitor(address string) *dbusInhibitor {
+	return &dbusInhibitor{address: address}
+}
+
+func (d *dbusInhibitor) connect() (*dbus.Conn, error) {
+	if d.address == "" {
+		return dbus.ConnectSessionBus()
+	}
+	return dbus.Connect(d.address)
+}
+
+// acquire 建立新連線並取得 inhibit cookie，成功時更新 d.conn / d.service / d.cookie。
+// 呼叫端必須持有 d.mu。
+func (d *dbusInhibitor) acquire() (*dbus.Conn, error) {
+	conn, err := d.connect()
+	if err != nil {
+		return nil, fmt.Errorf("connect session bus failed: %w", err)
+	}
+
+	var errs []error
+	for i := range dbusInhibitServices {
+		svc := &dbusInhibitServices[i]
+		var cookie uint32
+		call := conn.Object(svc.dest, svc.path).Call(svc.inhibit, 0, svc.args(dbusAppName, dbusInhibitReason)...)
+		if err := call.Store(&cookie); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", svc.dest, err))
+			continue
+		}
+		d.conn = conn
+		d.service = svc
+		d.cookie = cookie
+		return conn, nil
+	}
+	conn.Close()
+	return nil, fmt.Errorf("no D-Bus inhibit service available: %w", errors.Join(errs...))
+}
+
+// Inhibit 取得 inhibit cookie 並在背景監看連線，已持有時不重複取得
+func (d *dbusInhibitor) Inhibit() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stop != nil {
+		return nil
+	}
+	conn, err := d.acquire()
+	if err != nil {
+		return err
+	}
+	d.stop = make(chan struct{})
+	go d.watch(conn, d.stop)
+	logger.LogInfof("Linux/D-Bus: inhibit acquired via %s (cookie=%d)", d.service.dest, d.cookie)
+	return nil
+}
+
+// Release 解除 inhibit 並關閉連線
+func (d *dbusInhibitor) Release() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stop == nil {
+		return nil
+	}
+	close(d.stop)
+	d.stop = nil
+
+	if d.conn == nil || !d.conn.Connected() {
+		// 連線已中斷，服務端已自動解除 inhibit
+		d.conn = nil
+		return nil
+	}
+	call := d.conn.Object(d.service.dest, d.service.path).Call(d.service.uninhibit, 0, d.cookie)
+	d.conn.Close()
+	d.conn = nil
+	if call.Err != nil {
+		return fmt.Errorf("%s failed: %w", d.service.uninhibit, call.Err)
+	}
+	logger.LogInfof("Linux/D-Bus: inhibit released via %s", d.service.dest)
+	return nil
+}
+
+// watch 在連線中斷時以指數退避重新連線並重新取得 inhibit，直到 stop 被關閉
+func (d *dbusInhibitor) watch(conn *dbus.Conn, stop chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-conn.Context().Done():
+			// Release 先關閉 stop 再關閉連線，兩者同時就緒時 select 可能選到 Done
+			select {
+			case <-stop:
+				return
+			default:
+			}
+		}
+		logger.LogError("Linux/D-Bus: session bus connection lost, re-acquiring inhibit")
+
+		delay := dbusReconnectMin
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(delay):
+			}
+
+			d.mu.Lock()
+			select {
+			case <-stop:
+				d.mu.Unlock()
+				return
+			default:
+			}
+			newConn, err := d.acquire()
+			if err == nil {
+				logger.LogInfof("Linux/D-Bus: inhibit re-acquired via %s (cookie=%d)", d.service.dest, d.cookie)
+			}
+			d.mu.Unlock()
+
+			if err == nil {
+				conn = newConn
+				break
+			}
+			logger.LogError("Linux/D-Bus: re-acquire inhibit failed:", err)
+			if delay *= 2; delay > dbusReconnectMax {
+				delay = dbusReconnectMax
+			}
+		}
+	}
+}
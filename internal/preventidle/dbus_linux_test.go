@@ -0,0 +1,289 @@
+//go:build linux
+// +build linux
+
+package preventidle
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+
+	"github.com/HanksJCTsai/goidleguard/pkg/logger"
+)
+
+const testBusConfig = `<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:path=%s</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>
+`
+
+// testBus 為測試專用的私有 dbus-daemon
+type testBus struct {
+	t       *testing.T
+	config  string
+	address string
+	cmd     *exec.Cmd
+}
+
+func startTestBus(t *testing.T) *testBus {
+	t.Helper()
+	if _, err := exec.LookPath("dbus-daemon"); err != nil {
+		t.Skip("dbus-daemon not found; skipping D-Bus tests")
+	}
+
+	dir := t.TempDir()
+	config := filepath.Join(dir, "bus.conf")
+	if err := os.WriteFile(config, []byte(fmt.Sprintf(testBusConfig, filepath.Join(dir, "bus"))), 0644); err != nil {
+		t.Fatalf("Failed to write bus config: %v", err)
+	}
+	b := &testBus{t: t, config: config}
+	b.start()
+	t.Cleanup(b.stop)
+	return b
+}
+
+func (b *testBus) start() {
+	b.t.Helper()
+	cmd := exec.Command("dbus-daemon", "--config-file="+b.config, "--nofork", "--print-address")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		b.t.Fatalf("Failed to get dbus-daemon stdout: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		b.t.Fatalf("Failed to start dbus-daemon: %v", err)
+	}
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		cmd.Process.Kill()
+		b.t.Fatalf("Failed to read dbus-daemon address: %v", err)
+	}
+	b.cmd = cmd
+	b.address = strings.TrimSpace(address)
+}
+
+func (b *testBus) stop() {
+	if b.cmd == nil {
+		return
+	}
+	b.cmd.Process.Signal(os.Interrupt)
+	b.cmd.Process.Kill()
+	b.cmd.Wait()
+	b.cmd = nil
+}
+
+// stubInhibitService 模擬 org.freedesktop.ScreenSaver 或 org.gnome.SessionManager
+type stubInhibitService struct {
+	mu        sync.Mutex
+	next      uint32
+	active    map[uint32]bool
+	inhibits  int
+	releases  int
+	lastFlags uint32
+}
+
+func (s *stubInhibitService) add() uint32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next++
+	s.inhibits++
+	s.active[s.next] = true
+	return s.next
+}
+
+func (s *stubInhibitService) remove(cookie uint32) *dbus.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.active[cookie] {
+		return dbus.MakeFailedError(fmt.Errorf("unknown cookie %d", cookie))
+	}
+	delete(s.active, cookie)
+	s.releases++
+	return nil
+}
+
+func (s *stubInhibitService) counts() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.inhibits, s.releases
+}
+
+type stubScreenSaver struct{ *stubInhibitService }
+
+func (s stubScreenSaver) Inhibit(app, reason string) (uint32, *dbus.Error) {
+	return s.add(), nil
+}
+
+func (s stubScreenSaver) UnInhibit(cookie uint32) *dbus.Error {
+	return s.remove(cookie)
+}
+
+type stubSessionManager struct{ *stubInhibitService }
+
+func (s stubSessionManager) Inhibit(app string, xid uint32, reason string, flags uint32) (uint32, *dbus.Error) {
+	s.mu.Lock()
+	s.lastFlags = flags
+	s.mu.Unlock()
+	return s.add(), nil
+}
+
+func (s stubSessionManager) Uninhibit(cookie uint32) *dbus.Error {
+	return s.remove(cookie)
+}
+
+// serveStub 以 name 在 bus 上註冊 stub 服務並於 path 匯出 iface，回傳的連線關閉即代表服務離開 bus
+func serveStub(t *testing.T, address, name string, path dbus.ObjectPath, iface string, obj interface{}) *dbus.Conn {
+	t.Helper()
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatalf("Failed to connect stub to bus: %v", err)
+	}
+	if err := conn.Export(obj, path, iface); err != nil {
+		t.Fatalf("Failed to export stub: %v", err)
+	}
+	if reply, err := conn.RequestName(name, dbus.NameFlagDoNotQueue); err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("Failed to own %s: reply=%v err=%v", name, reply, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func newStub() *stubInhibitService {
+	return &stubInhibitService{active: make(map[uint32]bool)}
+}
+
+func TestDBusInhibitor_ScreenSaver(t *testing.T) {
+	bus := startTestBus(t)
+	stub := newStub()
+	serveStub(t, bus.address, "org.freedesktop.ScreenSaver", "/org/freedesktop/ScreenSaver", "org.freedesktop.ScreenSaver", stubScreenSaver{stub})
+
+	inh := newDBusInhibitor(bus.address)
+	if err := inh.Inhibit(); err != nil {
+		t.Fatalf("Inhibit failed: %v", err)
+	}
+	// 重複呼叫不應再次取得 cookie
+	if err := inh.Inhibit(); err != nil {
+		t.Fatalf("second Inhibit failed: %v", err)
+	}
+	if err := inh.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+
+	if inhibits, releases := stub.counts(); inhibits != 1 || releases != 1 {
+		t.Errorf("Expected 1 inhibit and 1 release, got %d and %d", inhibits, releases)
+	}
+}
+
+func TestDBusInhibitor_GnomeFallback(t *testing.T) {
+	bus := startTestBus(t)
+	stub := newStub()
+	serveStub(t, bus.address, "org.gnome.SessionManager", "/org/gnome/SessionManager", "org.gnome.SessionManager", stubSessionManager{stub})
+
+	inh := newDBusInhibitor(bus.address)
+	if err := inh.Inhibit(); err != nil {
+		t.Fatalf("Inhibit failed: %v", err)
+	}
+	if err := inh.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+
+	if inhibits, releases := stub.counts(); inhibits != 1 || releases != 1 {
+		t.Errorf("Expected 1 inhibit and 1 release, got %d and %d", inhibits, releases)
+	}
+	if stub.lastFlags&gnomeInhibitIdle == 0 {
+		t.Errorf("Expected idle inhibit flag to be set, got %d", stub.lastFlags)
+	}
+}
+
+func TestDBusInhibitor_NoService(t *testing.T) {
+	bus := startTestBus(t)
+
+	inh := newDBusInhibitor(bus.address)
+	if err := inh.Inhibit(); err == nil {
+		inh.Release()
+		t.Fatal("Expected error when no inhibit service is on the bus, got nil")
+	}
+}
+
+// logBuffer 收集 logger 的輸出，可同時被多個 goroutine 寫入
+type logBuffer struct {
+	mu  sync.Mutex
+	buf strings.Builder
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *logBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestDBusInhibitor_ReleaseDoesNotReacquire(t *testing.T) {
+	bus := startTestBus(t)
+	logs := &logBuffer{}
+	logger.SetOutput(logs)
+	t.Cleanup(logger.InitLogger)
+
+	// Release 關閉 stop 與連線後 watcher 才執行到 select：兩個 case 同時就緒
+	inh := newDBusInhibitor(bus.address)
+	for i := 0; i < 20; i++ {
+		conn, err := inh.connect()
+		if err != nil {
+			t.Fatalf("connect failed: %v", err)
+		}
+		stop := make(chan struct{})
+		close(stop)
+		conn.Close()
+		inh.watch(conn, stop)
+	}
+	if got := logs.String(); strings.Contains(got, "connection lost") {
+		t.Errorf("Expected a normal release not to be treated as a lost connection, got logs:\n%s", got)
+	}
+}
+
+func TestDBusInhibitor_ReacquireAfterBusRestart(t *testing.T) {
+	bus := startTestBus(t)
+	first := newStub()
+	serveStub(t, bus.address, "org.freedesktop.ScreenSaver", "/org/freedesktop/ScreenSaver", "org.freedesktop.ScreenSaver", stubScreenSaver{first})
+
+	inh := newDBusInhibitor(bus.address)
+	if err := inh.Inhibit(); err != nil {
+		t.Fatalf("Inhibit failed: %v", err)
+	}
+	defer inh.Release()
+
+	// 重新啟動 bus，模擬 session bus 連線中斷
+	bus.stop()
+	bus.start()
+	second := newStub()
+	serveStub(t, bus.address, "org.freedesktop.ScreenSaver", "/org/freedesktop/ScreenSaver", "org.freedesktop.ScreenSaver", stubScreenSaver{second})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if inhibits, _ := second.counts(); inhibits == 1 {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatal("Expected inhibit to be re-acquired after the bus restarted")
+}
@@ -0,0 +1,108 @@
+package preventidle
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/HanksJCTsai/goidleguard/pkg/logger"
+)
+
+// DryRunBackendName 為 dry-run backend 的註冊名稱
+const DryRunBackendName = "dryrun"
+
+// dryRunMaxRecords 為 dry-run backend 保留的最近記錄數量，避免長時間執行時記錄無限增長
+const dryRunMaxRecords = 1000
+
+// DryRunBackend 不會送出任何輸入或持有電源宣告，只記錄原本會執行的動作，
+// 用於在共用機器上調整排程。閒置時間來自合成時鐘：從建立或上一次「模擬輸入」起算，
+// 因此 Controller 的排程判斷、閒置查詢與門檻判斷都會照常運作。
+type DryRunBackend struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	lastInput time.Time
+	records   []DryRunRecord // 環狀緩衝區，最多 dryRunMaxRecords 筆
+	total     int            // 目前為止記錄的總次數
+}
+
+func NewDryRunBackend() *DryRunBackend {
+	return &DryRunBackend{now: time.Now, lastInput: time.Now()}
+}
+
+// Backend 將 DryRunBackend 包裝成 Backend，三個元件都由同一個 DryRunBackend 提供
+func (d *DryRunBackend) Backend() *Backend {
+	return &Backend{Name: DryRunBackendName, Idle: d, Input: d, Inhibitor: d}
+}
+
+// SetClock 替換合成時鐘的時間來源，並以新時鐘的現在時間作為上一次輸入
+func (d *DryRunBackend) SetClock(now func() time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.now = now
+	d.lastInput = now()
+}
+
+// IdleTime 回傳合成時鐘的閒置時間
+func (d *DryRunBackend) IdleTime() (time.Duration, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.now().Sub(d.lastInput), nil
+}
+
+// SendInput 記錄原本會送出的輸入，並如同真實輸入一樣將合成閒置時間歸零
+func (d *DryRunBackend) SendInput(in Input) error {
+	switch in.Type {
+	case "key", "mouse", "move", "wheel", "scroll":
+	default:
+		return errors.New("unsupported mode for dry-run backend: " + in.Type)
+	}
+	d.record(in.String())
+	logger.LogInfof("DryRun: would simulate %s", in)
+
+	d.mu.Lock()
+	d.lastInput = d.now()
+	d.mu.Unlock()
+	return nil
+}
+
+func (d *DryRunBackend) Inhibit() error {
+	d.record("inhibit")
+	logger.LogInfo("DryRun: would assert power inhibitor")
+	return nil
+}
+
+func (d *DryRunBackend) Release() error {
+	d.record("release")
+	logger.LogInfo("DryRun: would release power inhibitor")
+	return nil
+}
+
+// Records 依時間順序回傳最近 dryRunMaxRecords 筆記錄的動作
+func (d *DryRunBackend) Records() []DryRunRecord {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	i := d.total % dryRunMaxRecords
+	if len(d.records) < dryRunMaxRecords || i == 0 {
+		return append([]DryRunRecord(nil), d.records...)
+	}
+	return append(append([]DryRunRecord(nil), d.records[i:]...), d.records[:i]...)
+}
+
+// RecordCount 回傳目前為止記錄的總次數，包含已被捨棄的舊記錄
+func (d *DryRunBackend) RecordCount() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.total
+}
+
+func (d *DryRunBackend) record(action string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	r := DryRunRecord{Time: d.now(), Action: action}
+	if len(d.records) < dryRunMaxRecords {
+		d.records = append(d.records, r)
+	} else {
+		d.records[d.total%dryRunMaxRecords] = r
+	}
+	d.total++
+}
@@ -0,0 +1,89 @@
+package preventidle
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDryRunBackend_SyntheticIdleClock(t *testing.T) {
+	start := time.Date(2025, time.April, 7, 9, 0, 0, 0, time.Local)
+	now := start
+	d := NewDryRunBackend()
+	d.SetClock(func() time.Time { return now })
+
+	now = start.Add(3 * time.Second)
+	if idle, _ := d.IdleTime(); idle != 3*time.Second {
+		t.Fatalf("Expected 3s idle, got %v", idle)
+	}
+
+	if err := SimulateActivityWith(d, "mixed", SimulateOptions{Key: Key{Name: "f15"}}); err != nil {
+		t.Fatalf("SimulateActivityWith failed: %v", err)
+	}
+	if idle, _ := d.IdleTime(); idle != 0 {
+		t.Errorf("Expected idle to be reset by simulated input, got %v", idle)
+	}
+
+	d.Inhibit()
+	d.Release()
+	var got []string
+	for _, r := range d.Records() {
+		got = append(got, r.Action)
+	}
+	want := []string{"key f15", "mouse nudge(1)", "inhibit", "release"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected records %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Record %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
+
+func TestDryRunBackend_RecordsBounded(t *testing.T) {
+	start := time.Date(2025, time.April, 7, 9, 0, 0, 0, time.Local)
+	now := start
+	d := NewDryRunBackend()
+	d.SetClock(func() time.Time { return now })
+
+	const n = dryRunMaxRecords + 5
+	for i := 0; i < n; i++ {
+		now = start.Add(time.Duration(i) * time.Second)
+		d.Inhibit()
+	}
+	if got := d.RecordCount(); got != n {
+		t.Errorf("Expected %d records counted, got %d", n, got)
+	}
+	got := d.Records()
+	if len(got) != dryRunMaxRecords {
+		t.Fatalf("Expected %d records kept, got %d", dryRunMaxRecords, len(got))
+	}
+	// 只保留最近的記錄，且依時間順序排列
+	for i, r := range got {
+		if want := start.Add(time.Duration(i+5) * time.Second); !r.Time.Equal(want) {
+			t.Fatalf("Record %d: expected time %v, got %v", i, want, r.Time)
+		}
+	}
+}
+
+func TestDryRunBackend_SequenceWaitCancelled(t *testing.T) {
+	d := NewDryRunBackend()
+	cancel := make(chan struct{})
+	close(cancel)
+	opts := SimulateOptions{
+		Sequence: []Step{
+			{Action: "key", Key: Key{Name: "f15"}},
+			{Action: "wait", Wait: time.Hour},
+			{Action: "key", Key: Key{Name: "f15"}},
+		},
+		Cancel: cancel,
+	}
+	err := SimulateActivityWith(d, "sequence", opts)
+	if !errors.Is(err, ErrSimulationCancelled) {
+		t.Fatalf("Expected ErrSimulationCancelled, got %v", err)
+	}
+	if got := d.Records(); len(got) != 1 {
+		t.Errorf("Expected only the first step to be recorded, got %v", got)
+	}
+}
@@ -2,6 +2,7 @@ package preventidle
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/HanksJCTsai/goidleguard/pkg/logger"
 )
@@ -12,3 +13,15 @@ func HandleError(err error) {
 	msg := fmt.Sprintf("Idle prevention error: %v", err)
 	logger.LogError(msg)
 }
+
+func (e *UinputPermissionError) Error() string {
+	return fmt.Sprintf("permission denied opening %s: %v (add the user to the 'input' group or install a udev rule granting write access)", e.Path, e.Err)
+}
+
+func (e *UinputPermissionError) Unwrap() error {
+	return e.Err
+}
+
+func (e *UserActiveError) Error() string {
+	return fmt.Sprintf("user input %v ago, cancelled %s and remaining steps", e.Idle.Round(time.Millisecond), e.Input)
+}
@@ -0,0 +1,131 @@
+package preventidle
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// FakeBackendName 為記憶體假 backend 的名稱，只在測試中註冊，設定檔無法選用
+const FakeBackendName = "fake"
+
+// FakeBackend 是完全在記憶體中運作的 backend，不會碰觸任何輸入裝置或電源設定，
+// 供 daemon 的單元測試以可預期的閒置時間與錯誤驅動流程。
+type FakeBackend struct {
+	mu         sync.Mutex
+	idle       time.Duration
+	idleErr    error
+	inputErr   error
+	inhibitErr error
+	inputs     []Input
+	inhibited  bool
+	inhibits   int
+	releases   int
+}
+
+func NewFakeBackend() *FakeBackend {
+	return &FakeBackend{}
+}
+
+// Backend 將 FakeBackend 包裝成 Backend，三個元件都由同一個 FakeBackend 提供
+func (f *FakeBackend) Backend() *Backend {
+	return &Backend{Name: FakeBackendName, Idle: f, Input: f, Inhibitor: f}
+}
+
+// SetIdle 設定 IdleTime 回傳的閒置時間
+func (f *FakeBackend) SetIdle(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.idle = d
+}
+
+// SetIdleError 設定 IdleTime 回傳的錯誤，nil 表示恢復正常
+func (f *FakeBackend) SetIdleError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.idleErr = err
+}
+
+// SetInputError 設定 SendInput 回傳的錯誤，nil 表示恢復正常
+func (f *FakeBackend) SetInputError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inputErr = err
+}
+
+// SetInhibitError 設定 Inhibit 回傳的錯誤，nil 表示恢復正常
+func (f *FakeBackend) SetInhibitError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inhibitErr = err
+}
+
+func (f *FakeBackend) IdleTime() (time.Duration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.idleErr != nil {
+		return 0, f.idleErr
+	}
+	return f.idle, nil
+}
+
+// SendInput 記錄輸入並如同真實系統一樣將閒置時間歸零
+func (f *FakeBackend) SendInput(in Input) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.inputErr != nil {
+		return f.inputErr
+	}
+	switch in.Type {
+	case "key", "mouse", "move", "wheel", "scroll":
+	default:
+		return errors.New("unsupported mode for fake backend: " + in.Type)
+	}
+	f.inputs = append(f.inputs, in)
+	f.idle = 0
+	return nil
+}
+
+func (f *FakeBackend) Inhibit() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.inhibitErr != nil {
+		return f.inhibitErr
+	}
+	f.inhibited = true
+	f.inhibits++
+	return nil
+}
+
+func (f *FakeBackend) Release() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inhibited = false
+	f.releases++
+	return nil
+}
+
+// Inputs 回傳目前為止送出的輸入類型
+func (f *FakeBackend) Inputs() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	types := make([]string, len(f.inputs))
+	for i, in := range f.inputs {
+		types[i] = in.Type
+	}
+	return types
+}
+
+// Sent 回傳目前為止送出的完整輸入內容
+func (f *FakeBackend) Sent() []Input {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Input(nil), f.inputs...)
+}
+
+// Inhibited 回傳目前是否持有宣告，以及 Inhibit / Release 各被呼叫幾次
+func (f *FakeBackend) Inhibited() (held bool, inhibits, releases int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.inhibited, f.inhibits, f.releases
+}
@@ -0,0 +1,8 @@
+package preventidle
+
+// fake backend 只在測試中註冊，正式程式的 registry 不包含它
+func init() {
+	RegisterBackend(FakeBackendName, func() (*Backend, error) {
+		return NewFakeBackend().Backend(), nil
+	})
+}
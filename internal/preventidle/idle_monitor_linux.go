@@ -0,0 +1,108 @@
+//go:build linux
+// +build linux
+
+package preventidle
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	mutterIdleDest = "org.gnome.Mutter.IdleMonitor"
+	mutterIdlePath = dbus.ObjectPath("/org/gnome/Mutter/IdleMonitor/Core")
+)
+
+// sessionIdleMonitor 在沒有 X server 可查詢時 (Wayland、console) 取得使用者的閒置時間：
+// GNOME 查詢 Mutter 的 IdleMonitor (compositor 的閒置計數，uinput 注入的輸入也會重置)，
+// console session 則以終端機的最後存取時間計算。logind 的 IdleHint 只在螢幕關閉或鎖定時才設定，
+// 無法用來判斷是否達到閒置門檻，因此都無法使用時回傳錯誤，而不是回傳 0。
+type sessionIdleMonitor struct {
+	// address 為空字串時使用預設 session bus
+	address string
+	// session 提供 console session 的終端機名稱，devRoot 為終端機裝置所在的目錄
+	session *LogindSession
+	devRoot string
+
+	mu   sync.Mutex
+	conn *dbus.Conn
+}
+
+func newSessionIdleMonitor() *sessionIdleMonitor {
+	return &sessionIdleMonitor{session: NewLogindSession(), devRoot: "/dev"}
+}
+
+// IdleTime 依序嘗試 Mutter IdleMonitor 與 console 終端機的存取時間
+func (m *sessionIdleMonitor) IdleTime() (time.Duration, error) {
+	idle, mutterErr := m.mutterIdle()
+	if mutterErr == nil {
+		return idle, nil
+	}
+	idle, ttyErr := m.ttyIdle()
+	if ttyErr == nil {
+		return idle, nil
+	}
+	return 0, fmt.Errorf("no idle time source available without X11: %w", errors.Join(mutterErr, ttyErr))
+}
+
+// mutterIdle 呼叫 org.gnome.Mutter.IdleMonitor.GetIdletime (毫秒)；連線中斷時下次查詢重新連線
+func (m *sessionIdleMonitor) mutterIdle() (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.conn == nil || !m.conn.Connected() {
+		var conn *dbus.Conn
+		var err error
+		if m.address == "" {
+			conn, err = dbus.ConnectSessionBus()
+		} else {
+			conn, err = dbus.Connect(m.address)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("connect session bus failed: %w", err)
+		}
+		m.conn = conn
+	}
+	var ms uint64
+	if err := m.conn.Object(mutterIdleDest, mutterIdlePath).Call(mutterIdleDest+".GetIdletime", 0).Store(&ms); err != nil {
+		return 0, fmt.Errorf("%s.GetIdletime failed: %w", mutterIdleDest, err)
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// ttyIdle 以 console session 終端機的最後存取時間計算閒置時間，與 logind 判斷 tty session 閒置的方式相同
+func (m *sessionIdleMonitor) ttyIdle() (time.Duration, error) {
+	tty, err := m.session.ConsoleTTY()
+	if err != nil {
+		return 0, err
+	}
+	if tty == "" {
+		return 0, errors.New("not a console session")
+	}
+	var st syscall.Stat_t
+	if err := syscall.Stat(filepath.Join(m.devRoot, tty), &st); err != nil {
+		return 0, fmt.Errorf("stat %s failed: %w", tty, err)
+	}
+	d := time.Since(time.Unix(st.Atim.Unix()))
+	if d < 0 {
+		return 0, nil
+	}
+	return d, nil
+}
+
+// Close 關閉 session bus 與 system bus 的連線
+func (m *sessionIdleMonitor) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.conn != nil {
+		m.conn.Close()
+		m.conn = nil
+	}
+	return m.session.Close()
+}
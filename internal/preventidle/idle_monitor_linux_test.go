@@ -0,0 +1,101 @@
+//go:build linux
+// +build linux
+
+package preventidle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/prop"
+)
+
+// stubMutterIdleMonitor 模擬 org.gnome.Mutter.IdleMonitor，回傳固定的閒置毫秒數
+type stubMutterIdleMonitor struct {
+	idle time.Duration
+}
+
+func (s stubMutterIdleMonitor) GetIdletime() (uint64, *dbus.Error) {
+	return uint64(s.idle / time.Millisecond), nil
+}
+
+// useSessionIdle 將 GetIdleTime 在沒有 X server 時使用的來源替換成連到測試 bus 的 sessionIdleMonitor，
+// 測試 bus 同時扮演 session bus 與 system bus
+func useSessionIdle(t *testing.T, address, devRoot string) {
+	t.Helper()
+	t.Setenv("DISPLAY", "")
+	t.Setenv("WAYLAND_DISPLAY", "wayland-0")
+	saved := sessionIdle
+	sessionIdle = &sessionIdleMonitor{address: address, session: &LogindSession{address: address}, devRoot: devRoot}
+	t.Cleanup(func() {
+		sessionIdle.Close()
+		sessionIdle = saved
+	})
+}
+
+// exportSession 在測試 bus 上提供 logind 的 session 屬性
+func exportSession(t *testing.T, address string, props map[string]interface{}) {
+	t.Helper()
+	conn := serveStub(t, address, logindDest, logindPath, logindDest+".Manager", stubLogindManager{})
+	m := map[string]*prop.Prop{}
+	for name, v := range props {
+		m[name] = &prop.Prop{Value: v, Emit: prop.EmitTrue}
+	}
+	if _, err := prop.Export(conn, stubSessionPath, prop.Map{logindDest + ".Session": m}); err != nil {
+		t.Fatalf("Failed to export session properties: %v", err)
+	}
+}
+
+func TestGetIdleTime_WaylandMutter(t *testing.T) {
+	bus := startTestBus(t)
+	// compositor 在螢幕關閉前都不會設定 IdleHint
+	exportSession(t, bus.address, map[string]interface{}{"Type": "wayland", "IdleHint": false})
+	serveStub(t, bus.address, mutterIdleDest, mutterIdlePath, mutterIdleDest, stubMutterIdleMonitor{idle: 6 * time.Minute})
+	useSessionIdle(t, bus.address, t.TempDir())
+
+	// idlePrevention.interval 為 5 分鐘時，IdleHint 仍為 false 也應達到門檻
+	idle, err := GetIdleTime()
+	if err != nil {
+		t.Fatalf("GetIdleTime failed: %v", err)
+	}
+	if idle != 6*time.Minute {
+		t.Errorf("Expected 6m of idle time from Mutter, got %v", idle)
+	}
+}
+
+func TestGetIdleTime_Console(t *testing.T) {
+	bus := startTestBus(t)
+	exportSession(t, bus.address, map[string]interface{}{"Type": "tty", "TTY": "tty3", "IdleHint": false})
+	dev := t.TempDir()
+	tty := filepath.Join(dev, "tty3")
+	if err := os.WriteFile(tty, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	last := time.Now().Add(-10 * time.Minute)
+	if err := os.Chtimes(tty, last, last); err != nil {
+		t.Fatal(err)
+	}
+	useSessionIdle(t, bus.address, dev)
+
+	idle, err := GetIdleTime()
+	if err != nil {
+		t.Fatalf("GetIdleTime failed: %v", err)
+	}
+	if idle < 10*time.Minute || idle > 11*time.Minute {
+		t.Errorf("Expected about 10m of idle time from the terminal access time, got %v", idle)
+	}
+}
+
+func TestGetIdleTime_NoSource(t *testing.T) {
+	bus := startTestBus(t)
+	exportSession(t, bus.address, map[string]interface{}{"Type": "wayland", "IdleHint": false})
+	useSessionIdle(t, bus.address, t.TempDir())
+
+	// 沒有可用的閒置計數時回傳錯誤，而不是看起來像使用者剛有輸入的 0
+	if idle, err := GetIdleTime(); err == nil {
+		t.Errorf("Expected an error without an idle time source, got %v", idle)
+	}
+}
@@ -0,0 +1,82 @@
+package preventidle
+
+import (
+	"sync"
+	"time"
+)
+
+// injectSlack 為系統記錄我們送出的輸入時可能產生的時間誤差
+const injectSlack = 100 * time.Millisecond
+
+// IdleTracker 區分 GoIdleGuard 自己送出的模擬輸入與使用者真正的輸入。
+// 系統閒置時間會被模擬輸入歸零，因此記錄每次注入的時間區間：
+// 系統回報的最後輸入時間落在注入區間內時視為我們的輸入，其餘才更新「使用者最後輸入時間」。
+// 每次 tick 都會查詢閒置時間，且 tick 不大於閒置門檻，因此使用者的輸入在下一次注入前一定會被觀察到。
+type IdleTracker struct {
+	mu          sync.Mutex
+	source      IdleSource
+	now         func() time.Time
+	injectStart time.Time
+	injectEnd   time.Time
+	lastReal    time.Time
+}
+
+// NewIdleTracker 建立 IdleTracker，now 為 nil 時使用 time.Now
+func NewIdleTracker(source IdleSource, now func() time.Time) *IdleTracker {
+	if now == nil {
+		now = time.Now
+	}
+	return &IdleTracker{source: source, now: now}
+}
+
+// Track 包裝 inj，記錄經由它送出的每次輸入的時間區間
+func (t *IdleTracker) Track(inj InputInjector) InputInjector {
+	return InputFunc(func(in Input) error {
+		start := t.now()
+		err := inj.SendInput(in)
+		end := t.now()
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		// 同一次 SimulateActivity 的多個輸入合併成一個區間
+		if t.injectEnd.IsZero() || start.After(t.injectEnd.Add(injectSlack)) {
+			t.injectStart = start
+		}
+		t.injectEnd = end
+		return err
+	})
+}
+
+// IdleTime 回傳系統閒置時間，讓 IdleTracker 可直接當作 IdleSource 使用
+func (t *IdleTracker) IdleTime() (time.Duration, error) {
+	system, _, err := t.Idle()
+	return system, err
+}
+
+// Idle 回傳系統閒置時間 (包含我們的模擬輸入) 與使用者真正的閒置時間
+func (t *IdleTracker) Idle() (system, user time.Duration, err error) {
+	system, err = t.source.IdleTime()
+	if err != nil {
+		return 0, 0, err
+	}
+	now := t.now()
+	last := now.Add(-system)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.injected(last) && last.After(t.lastReal) {
+		t.lastReal = last
+	}
+	if t.lastReal.IsZero() {
+		return system, system, nil
+	}
+	return system, now.Sub(t.lastReal), nil
+}
+
+// injected 回傳 last 是否落在最近一次注入的時間區間內
+func (t *IdleTracker) injected(last time.Time) bool {
+	if t.injectEnd.IsZero() {
+		return false
+	}
+	return !last.Before(t.injectStart.Add(-injectSlack)) && !last.After(t.injectEnd.Add(injectSlack))
+}
@@ -0,0 +1,37 @@
+package preventidle
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIdleTracker_ExcludesInjectedInput(t *testing.T) {
+	start := time.Date(2025, time.April, 7, 9, 0, 0, 0, time.Local)
+	now := start
+	fake := NewFakeBackend()
+	tracker := NewIdleTracker(fake, func() time.Time { return now })
+
+	// 使用者最後一次輸入在 9:00:00，兩秒後查詢
+	now = start.Add(2 * time.Second)
+	fake.SetIdle(2 * time.Second)
+	if system, user, _ := tracker.Idle(); system != 2*time.Second || user != 2*time.Second {
+		t.Fatalf("Expected 2s/2s before any injection, got %v/%v", system, user)
+	}
+
+	// 模擬輸入將系統閒置時間歸零，但使用者閒置時間繼續累加
+	if err := tracker.Track(fake).SendInput(Input{Type: "key"}); err != nil {
+		t.Fatalf("SendInput failed: %v", err)
+	}
+	now = start.Add(5 * time.Second)
+	fake.SetIdle(3 * time.Second)
+	if system, user, _ := tracker.Idle(); system != 3*time.Second || user != 5*time.Second {
+		t.Errorf("Expected 3s system / 5s user idle after injection, got %v/%v", system, user)
+	}
+
+	// 使用者回來後兩者一致
+	now = start.Add(6 * time.Second)
+	fake.SetIdle(500 * time.Millisecond)
+	if system, user, _ := tracker.Idle(); system != user || user != 500*time.Millisecond {
+		t.Errorf("Expected user input to reset both idle times, got %v/%v", system, user)
+	}
+}
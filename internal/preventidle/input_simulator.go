@@ -1,13 +1,24 @@
 package preventidle
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/HanksJCTsai/goidleguard/pkg/logger"
 )
 
+// ErrSimulationCancelled 表示 SimulateOptions.Cancel 已關閉，sequence 的剩餘步驟已取消
+var ErrSimulationCancelled = errors.New("simulation cancelled")
+
+// SimulateActivity 以平台原生的 CallSendInput 依 mode 模擬使用者活動
 func SimulateActivity(mode string) error {
+	return SimulateActivityWith(InputFunc(CallSendInput), mode, SimulateOptions{})
+}
+
+// SimulateActivityWith 透過指定的 InputInjector 依 mode 與 opts 模擬使用者活動
+func SimulateActivityWith(inj InputInjector, mode string, opts SimulateOptions) error {
 
 	var actions []SimulateAction
 	switch strings.ToUpper(mode) {
@@ -25,14 +36,97 @@ func SimulateActivity(mode string) error {
 		actions = []SimulateAction{
 			{"mouse", "mouse move"},
 		}
+	case "SCROLL":
+		// 滾輪向上再向下一格，淨位移為零；部分遠端桌面 / VDI 用戶端只認滾輪事件
+		actions = []SimulateAction{
+			{"scroll", "scroll"},
+		}
+	case "SEQUENCE":
+		return runSequence(inj, opts.Sequence, opts.Cancel)
+	case "INHIBIT":
+		// 由 PreventSleep / AllowIdle 的電源宣告負責防止閒置，不送出任何輸入
+		return nil
 	}
 
 	for _, a := range actions {
-		if err := CallSendInput(a.inputType); err != nil {
+		if err := inj.SendInput(Input{Type: a.inputType, Key: opts.Key, Mouse: opts.Mouse}); err != nil {
 			return fmt.Errorf("simulate %s failed: %w", a.actionName, err)
 		}
-		logger.LogInfo("Simulated %s", a.actionName)
+		logger.LogInfof("Simulated %s", a.actionName)
 	}
 	logger.LogInfo("Simulated combined activity")
 	return nil
 }
+
+// runSequence 依序執行 sequence 模式的步驟，每個步驟各記錄一筆 log；
+// wait 步驟期間 cancel 被關閉時回傳 ErrSimulationCancelled
+func runSequence(inj InputInjector, steps []Step, cancel <-chan struct{}) error {
+	if len(steps) == 0 {
+		return fmt.Errorf("sequence mode has no steps")
+	}
+	for i, step := range steps {
+		var err error
+		switch step.Action {
+		case "key":
+			err = inj.SendInput(Input{Type: "key", Key: step.Key})
+		case "move":
+			err = inj.SendInput(Input{Type: "move", Dx: step.Dx, Dy: step.Dy})
+		case "scroll":
+			err = inj.SendInput(Input{Type: "wheel", Wheel: step.Amount})
+		case "wait":
+			err = wait(step.Wait, cancel)
+		default:
+			err = fmt.Errorf("unsupported sequence action %q", step.Action)
+		}
+		if err != nil {
+			return fmt.Errorf("sequence step %d/%d (%s) failed: %w", i+1, len(steps), step, err)
+		}
+		logger.LogInfof("Sequence step %d/%d: %s", i+1, len(steps), step)
+	}
+	logger.LogInfo("Simulated sequence")
+	return nil
+}
+
+// wait 等待 d，cancel 先被關閉時回傳 ErrSimulationCancelled
+func wait(d time.Duration, cancel <-chan struct{}) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-cancel:
+		return ErrSimulationCancelled
+	}
+}
+
+// String 回傳步驟的描述，例如 "key ctrl+f15"、"move (5,-3)"
+func (s Step) String() string {
+	switch s.Action {
+	case "key":
+		return "key " + s.Key.String()
+	case "move":
+		return fmt.Sprintf("move (%d,%d)", s.Dx, s.Dy)
+	case "scroll":
+		return fmt.Sprintf("scroll %d", s.Amount)
+	case "wait":
+		return fmt.Sprintf("wait %v", s.Wait)
+	default:
+		return s.Action
+	}
+}
+
+// String 回傳輸入的描述，例如 "key ctrl+f15"、"mouse circle(5)"
+func (in Input) String() string {
+	switch in.Type {
+	case "key":
+		return "key " + in.Key.String()
+	case "mouse":
+		return "mouse " + in.Mouse.String()
+	case "move":
+		return fmt.Sprintf("move (%d,%d)", in.Dx, in.Dy)
+	case "wheel":
+		return fmt.Sprintf("wheel %d", in.Wheel)
+	default:
+		return in.Type
+	}
+}
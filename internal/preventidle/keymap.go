@@ -0,0 +1,40 @@
+package preventidle
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultKey 為未設定按鍵時送出的鍵；只按 Shift 不會在焦點視窗輸入任何文字
+var DefaultKey = Key{Name: "shift"}
+
+// String 回傳按鍵組合，例如 "ctrl+f15"
+func (k Key) String() string {
+	if k.Name == "" {
+		k = DefaultKey
+	}
+	parts := make([]string, 0, len(k.Modifiers)+1)
+	for _, m := range k.Modifiers {
+		parts = append(parts, strings.ToLower(m))
+	}
+	return strings.Join(append(parts, strings.ToLower(k.Name)), "+")
+}
+
+// resolveKey 以平台的按鍵對照表將 Key 轉換成主鍵與修飾鍵的 key code
+func resolveKey[T any](table map[string]T, platform string, k Key) (code T, mods []T, err error) {
+	if k.Name == "" {
+		k = DefaultKey
+	}
+	code, ok := table[strings.ToLower(k.Name)]
+	if !ok {
+		return code, nil, fmt.Errorf("key %q is not supported on %s", k.Name, platform)
+	}
+	for _, m := range k.Modifiers {
+		mc, ok := table[strings.ToLower(m)]
+		if !ok {
+			return code, nil, fmt.Errorf("modifier %q is not supported on %s", m, platform)
+		}
+		mods = append(mods, mc)
+	}
+	return code, mods, nil
+}
@@ -0,0 +1,80 @@
+//go:build darwin
+// +build darwin
+
+package preventidle
+
+// macKeyCodes 將可攜式按鍵名稱對應到 macOS 的虛擬按鍵代碼 (HIToolbox Events.h 的 kVK_*)。
+// Mac 鍵盤沒有 Scroll Lock / Pause，改用延伸鍵盤上相同位置的 F14 / F15；F21 以後沒有對應按鍵。
+var macKeyCodes = map[string]uint16{
+	"shift":      56, // kVK_Shift
+	"ctrl":       59, // kVK_Control
+	"alt":        58, // kVK_Option
+	"scrolllock": 107,
+	"pause":      113,
+	"space":      49,
+	"tab":        48,
+	"enter":      36, // kVK_Return
+	"f1":         122,
+	"f2":         120,
+	"f3":         99,
+	"f4":         118,
+	"f5":         96,
+	"f6":         97,
+	"f7":         98,
+	"f8":         100,
+	"f9":         101,
+	"f10":        109,
+	"f11":        103,
+	"f12":        111,
+	"f13":        105,
+	"f14":        107,
+	"f15":        113,
+	"f16":        106,
+	"f17":        64,
+	"f18":        79,
+	"f19":        80,
+	"f20":        90,
+	"a":          0,
+	"s":          1,
+	"d":          2,
+	"f":          3,
+	"h":          4,
+	"g":          5,
+	"z":          6,
+	"x":          7,
+	"c":          8,
+	"v":          9,
+	"b":          11,
+	"q":          12,
+	"w":          13,
+	"e":          14,
+	"r":          15,
+	"y":          16,
+	"t":          17,
+	"1":          18,
+	"2":          19,
+	"3":          20,
+	"4":          21,
+	"6":          22,
+	"5":          23,
+	"9":          25,
+	"7":          26,
+	"8":          28,
+	"0":          29,
+	"o":          31,
+	"u":          32,
+	"i":          34,
+	"p":          35,
+	"l":          37,
+	"j":          38,
+	"k":          40,
+	"n":          45,
+	"m":          46,
+}
+
+// macModifierFlags 為修飾鍵對應的 CGEventFlags，需設定在主鍵事件上修飾鍵才會生效
+var macModifierFlags = map[uint16]uint64{
+	56: 0x00020000, // kCGEventFlagMaskShift
+	59: 0x00040000, // kCGEventFlagMaskControl
+	58: 0x00080000, // kCGEventFlagMaskAlternate
+}
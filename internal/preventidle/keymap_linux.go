@@ -0,0 +1,81 @@
+//go:build linux
+// +build linux
+
+package preventidle
+
+import "fmt"
+
+// uinputKeyCodes 將可攜式按鍵名稱對應到 linux/input-event-codes.h 的 KEY_* 代碼
+var uinputKeyCodes = map[string]uint16{
+	"shift":      42, // KEY_LEFTSHIFT
+	"ctrl":       29, // KEY_LEFTCTRL
+	"alt":        56, // KEY_LEFTALT
+	"scrolllock": 70,
+	"pause":      119,
+	"space":      57,
+	"tab":        15,
+	"enter":      28,
+	"0":          11,
+	"a":          30,
+	"b":          48,
+	"c":          46,
+	"d":          32,
+	"e":          18,
+	"f":          33,
+	"g":          34,
+	"h":          35,
+	"i":          23,
+	"j":          36,
+	"k":          37,
+	"l":          38,
+	"m":          50,
+	"n":          49,
+	"o":          24,
+	"p":          25,
+	"q":          16,
+	"r":          19,
+	"s":          31,
+	"t":          20,
+	"u":          22,
+	"v":          47,
+	"w":          17,
+	"x":          45,
+	"y":          21,
+	"z":          44,
+}
+
+// x11Keysyms 將可攜式按鍵名稱對應到 X11 keysym
+var x11Keysyms = map[string]uint32{
+	"shift":      0xffe1, // XK_Shift_L
+	"ctrl":       0xffe3, // XK_Control_L
+	"alt":        0xffe9, // XK_Alt_L
+	"scrolllock": 0xff14,
+	"pause":      0xff13,
+	"space":      0x0020,
+	"tab":        0xff09,
+	"enter":      0xff0d, // XK_Return
+}
+
+func init() {
+	for i := 1; i <= 24; i++ {
+		name := fmt.Sprintf("f%d", i)
+		x11Keysyms[name] = 0xffbe + uint32(i-1) // XK_F1 起連續編號
+		switch {
+		case i <= 10:
+			uinputKeyCodes[name] = 59 + uint16(i-1) // KEY_F1..KEY_F10
+		case i <= 12:
+			uinputKeyCodes[name] = 87 + uint16(i-11) // KEY_F11, KEY_F12
+		default:
+			uinputKeyCodes[name] = 183 + uint16(i-13) // KEY_F13..KEY_F24
+		}
+	}
+	for c := 'a'; c <= 'z'; c++ {
+		x11Keysyms[string(c)] = uint32(c)
+	}
+	for c := '0'; c <= '9'; c++ {
+		x11Keysyms[string(c)] = uint32(c)
+	}
+	for c := '1'; c <= '9'; c++ {
+		uinputKeyCodes[string(c)] = 2 + uint16(c-'1') // KEY_1..KEY_9，KEY_0 為 11
+	}
+}
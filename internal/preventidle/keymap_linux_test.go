@@ -0,0 +1,40 @@
+//go:build linux
+// +build linux
+
+package preventidle
+
+import (
+	"testing"
+
+	"github.com/HanksJCTsai/goidleguard/internal/config"
+)
+
+func TestKeymap_CoversConfigKeyNames(t *testing.T) {
+	for name := range config.KeyNames {
+		if _, ok := uinputKeyCodes[name]; !ok {
+			t.Errorf("Key %q has no uinput key code", name)
+		}
+		if _, ok := x11Keysyms[name]; !ok {
+			t.Errorf("Key %q has no X11 keysym", name)
+		}
+	}
+}
+
+func TestResolveKey(t *testing.T) {
+	code, mods, err := resolveKey(uinputKeyCodes, "test", Key{Name: "F15", Modifiers: []string{"Ctrl"}})
+	if err != nil {
+		t.Fatalf("resolveKey failed: %v", err)
+	}
+	if code != 185 || len(mods) != 1 || mods[0] != 29 {
+		t.Errorf("Expected KEY_F15 with KEY_LEFTCTRL, got %d %v", code, mods)
+	}
+
+	// 未設定時使用 Shift
+	if code, _, _ := resolveKey(uinputKeyCodes, "test", Key{}); code != 42 {
+		t.Errorf("Expected default key to be KEY_LEFTSHIFT, got %d", code)
+	}
+
+	if _, _, err := resolveKey(uinputKeyCodes, "test", Key{Name: "shift", Modifiers: []string{"hyper"}}); err == nil {
+		t.Error("Expected error for unsupported modifier, got nil")
+	}
+}
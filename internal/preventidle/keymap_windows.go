@@ -0,0 +1,31 @@
+//go:build windows
+// +build windows
+
+package preventidle
+
+import "fmt"
+
+// vkCodes 將可攜式按鍵名稱對應到 Windows virtual-key code
+var vkCodes = map[string]uint16{
+	"shift":      0x10, // VK_SHIFT
+	"ctrl":       0x11, // VK_CONTROL
+	"alt":        0x12, // VK_MENU
+	"scrolllock": 0x91, // VK_SCROLL
+	"pause":      0x13, // VK_PAUSE
+	"space":      0x20, // VK_SPACE
+	"tab":        0x09, // VK_TAB
+	"enter":      0x0d, // VK_RETURN
+}
+
+func init() {
+	for i := 1; i <= 24; i++ {
+		vkCodes[fmt.Sprintf("f%d", i)] = 0x70 + uint16(i-1) // VK_F1..VK_F24
+	}
+	// 字母與數字的 virtual-key code 與大寫 ASCII 相同
+	for c := 'a'; c <= 'z'; c++ {
+		vkCodes[string(c)] = uint16(c - 'a' + 'A')
+	}
+	for c := '0'; c <= '9'; c++ {
+		vkCodes[string(c)] = uint16(c)
+	}
+}
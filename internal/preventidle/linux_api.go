@@ -0,0 +1,78 @@
+//go:build linux
+// +build linux
+
+package preventidle
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/HanksJCTsai/goidleguard/pkg/logger"
+)
+
+var (
+	inhibitMu sync.Mutex
+	// sessionInhibitor 為預設 session bus 上的 D-Bus inhibitor
+	sessionInhibitor = newDBusInhibitor("")
+	// x11Inhibiting 表示目前是以 XScreenSaverSuspend 作為退路持有宣告
+	x11Inhibiting bool
+	// sessionIdle 為沒有 X server 可查詢時的閒置時間來源
+	sessionIdle = newSessionIdleMonitor()
+)
+
+// useUinput 判斷是否改用 /dev/uinput 注入輸入：
+// Wayland 下 XTest 事件只會送到 XWayland，不會重置 compositor 的閒置計時；
+// console session 則根本沒有 X server。
+func useUinput() bool {
+	return os.Getenv("WAYLAND_DISPLAY") != "" || os.Getenv("DISPLAY") == ""
+}
+
+// PreventSleep 透過 org.freedesktop.ScreenSaver / org.gnome.SessionManager 的 Inhibit 防止閒置；
+// 沒有可用的 session bus 服務時，退回 XScreenSaverSuspend。
+func PreventSleep() error {
+	inhibitMu.Lock()
+	defer inhibitMu.Unlock()
+
+	err := sessionInhibitor.Inhibit()
+	if err == nil {
+		return nil
+	}
+	logger.LogError("Linux: D-Bus inhibit unavailable, falling back to X11:", err)
+	if err := x11PreventSleep(); err != nil {
+		return err
+	}
+	x11Inhibiting = true
+	return nil
+}
+
+// AllowIdle 解除 PreventSleep 取得的宣告
+func AllowIdle() error {
+	inhibitMu.Lock()
+	defer inhibitMu.Unlock()
+
+	if x11Inhibiting {
+		if err := x11AllowIdle(); err != nil {
+			return err
+		}
+		x11Inhibiting = false
+	}
+	return sessionInhibitor.Release()
+}
+
+// CallSendInput 模擬鍵盤或滑鼠事件；X11 session 使用 XTest，Wayland 與 console 使用 uinput 虛擬裝置
+func CallSendInput(in Input) error {
+	if useUinput() {
+		return uinputSendInput(in)
+	}
+	return x11SendInput(in)
+}
+
+// GetIdleTime 取得使用者閒置時間：X11 session 查詢 X server；
+// Wayland 與 console 改用 sessionIdleMonitor，XWayland 的閒置計數看不到 uinput 事件，console 則沒有 X server
+func GetIdleTime() (time.Duration, error) {
+	if useUinput() {
+		return sessionIdle.IdleTime()
+	}
+	return x11GetIdleTime()
+}
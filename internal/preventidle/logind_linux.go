@@ -0,0 +1,87 @@
+//go:build linux
+// +build linux
+
+package preventidle
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"syscall"
+
+	"github.com/HanksJCTsai/goidleguard/pkg/logger"
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	logindDest = "org.freedesktop.login1"
+	logindPath = "/org/freedesktop/login1"
+)
+
+// LogindInhibitor 透過 systemd-logind 的 Manager.Inhibit 取得 inhibitor lock。
+// logind 回傳的檔案描述子在關閉前都會阻擋 what 所列的動作 (例如 "idle:sleep")。
+type LogindInhibitor struct {
+	what, why, mode string
+	// address 為空字串時使用 system bus
+	address string
+
+	mu sync.Mutex
+	fd int
+}
+
+// NewLogindInhibitor 建立 logind inhibitor；what 例如 "idle:sleep"，mode 為 "block" 或 "delay"
+func NewLogindInhibitor(what, why, mode string) *LogindInhibitor {
+	return &LogindInhibitor{what: what, why: why, mode: mode, fd: -1}
+}
+
+func (l *LogindInhibitor) connect() (*dbus.Conn, error) {
+	if l.address == "" {
+		return dbus.ConnectSystemBus()
+	}
+	return dbus.Connect(l.address)
+}
+
+// Inhibit 取得 inhibitor lock，已持有時不重複取得
+func (l *LogindInhibitor) Inhibit() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.fd >= 0 {
+		return nil
+	}
+	conn, err := l.connect()
+	if err != nil {
+		return fmt.Errorf("connect system bus failed: %w", err)
+	}
+	// lock 由檔案描述子持有，與 bus 連線無關，取得後即可關閉連線
+	defer conn.Close()
+
+	var fd dbus.UnixFD
+	call := conn.Object(logindDest, logindPath).Call(logindDest+".Manager.Inhibit", 0, l.what, dbusAppName, l.why, l.mode)
+	if err := call.Store(&fd); err != nil {
+		return fmt.Errorf("logind Inhibit(%q, %q) failed: %w", l.what, l.mode, err)
+	}
+	if fd < 0 {
+		return errors.New("logind Inhibit returned an invalid file descriptor")
+	}
+	l.fd = int(fd)
+	logger.LogInfof("Linux/logind: inhibitor lock acquired (what=%s, mode=%s)", l.what, l.mode)
+	return nil
+}
+
+// Release 關閉檔案描述子以釋放 inhibitor lock
+func (l *LogindInhibitor) Release() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.fd < 0 {
+		return nil
+	}
+	err := syscall.Close(l.fd)
+	l.fd = -1
+	if err != nil {
+		return fmt.Errorf("close logind inhibitor fd failed: %w", err)
+	}
+	logger.LogInfof("Linux/logind: inhibitor lock released (what=%s)", l.what)
+	return nil
+}
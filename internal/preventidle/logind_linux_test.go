@@ -0,0 +1,93 @@
+//go:build linux
+// +build linux
+
+package preventidle
+
+import (
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// stubLogind 模擬 org.freedesktop.login1.Manager，Inhibit 回傳 pipe 的讀取端
+type stubLogind struct {
+	mu    sync.Mutex
+	calls [][4]string
+	pipes []*os.File
+}
+
+func (s *stubLogind) Inhibit(what, who, why, mode string) (dbus.UnixFD, *dbus.Error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return -1, dbus.MakeFailedError(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, [4]string{what, who, why, mode})
+	s.pipes = append(s.pipes, r, w)
+	return dbus.UnixFD(r.Fd()), nil
+}
+
+func fdIsOpen(fd int) bool {
+	_, _, errno := syscall.Syscall(syscall.SYS_FCNTL, uintptr(fd), syscall.F_GETFD, 0)
+	return errno == 0
+}
+
+func TestLogindInhibitor_InhibitAndRelease(t *testing.T) {
+	bus := startTestBus(t)
+	stub := &stubLogind{}
+	serveStub(t, bus.address, logindDest, logindPath, logindDest+".Manager", stub)
+	defer func() {
+		for _, f := range stub.pipes {
+			f.Close()
+		}
+	}()
+
+	inh := NewLogindInhibitor("idle:sleep", "unit test", "block")
+	inh.address = bus.address
+
+	if err := inh.Inhibit(); err != nil {
+		t.Fatalf("Inhibit failed: %v", err)
+	}
+	if err := inh.Inhibit(); err != nil {
+		t.Fatalf("second Inhibit failed: %v", err)
+	}
+	fd := inh.fd
+	if !fdIsOpen(fd) {
+		t.Fatalf("Expected inhibitor fd %d to be open", fd)
+	}
+
+	stub.mu.Lock()
+	calls := stub.calls
+	stub.mu.Unlock()
+	if len(calls) != 1 {
+		t.Fatalf("Expected 1 Inhibit call, got %d", len(calls))
+	}
+	if want := [4]string{"idle:sleep", dbusAppName, "unit test", "block"}; calls[0] != want {
+		t.Errorf("Expected Inhibit args %v, got %v", want, calls[0])
+	}
+
+	if err := inh.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if fdIsOpen(fd) {
+		t.Errorf("Expected inhibitor fd %d to be closed after Release", fd)
+	}
+	if err := inh.Release(); err != nil {
+		t.Errorf("second Release failed: %v", err)
+	}
+}
+
+func TestLogindInhibitor_NoService(t *testing.T) {
+	bus := startTestBus(t)
+
+	inh := NewLogindInhibitor("idle:sleep", "unit test", "block")
+	inh.address = bus.address
+	if err := inh.Inhibit(); err == nil {
+		inh.Release()
+		t.Fatal("Expected error when logind is not on the bus, got nil")
+	}
+}
@@ -0,0 +1,42 @@
+//go:build !linux
+// +build !linux
+
+package preventidle
+
+import "errors"
+
+// LogindInhibitor 在非 Linux 平台上沒有作用，Inhibit 一律回傳錯誤
+type LogindInhibitor struct{}
+
+// NewLogindInhibitor 建立 logind inhibitor；what 例如 "idle:sleep"，mode 為 "block" 或 "delay"
+func NewLogindInhibitor(what, why, mode string) *LogindInhibitor {
+	return &LogindInhibitor{}
+}
+
+// Inhibit 在非 Linux 平台上不支援
+func (l *LogindInhibitor) Inhibit() error {
+	return errors.New("systemd-logind inhibitor is only available on Linux")
+}
+
+// Release 在非 Linux 平台上不需釋放任何資源
+func (l *LogindInhibitor) Release() error {
+	return nil
+}
+
+// LogindSession 在非 Linux 平台上沒有作用，SessionState 一律回傳錯誤
+type LogindSession struct{}
+
+// NewLogindSession 建立讀取目前 session 狀態的 LogindSession
+func NewLogindSession() *LogindSession {
+	return &LogindSession{}
+}
+
+// SessionState 在非 Linux 平台上不支援
+func (s *LogindSession) SessionState() (SessionState, error) {
+	return SessionState{}, errors.New("systemd-logind session state is only available on Linux")
+}
+
+// Close 在非 Linux 平台上不需釋放任何資源
+func (s *LogindSession) Close() error {
+	return nil
+}
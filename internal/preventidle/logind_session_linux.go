@@ -0,0 +1,118 @@
+//go:build linux
+// +build linux
+
+package preventidle
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// logindAutoSession 為 logind 代表「呼叫者所屬 session」的特殊路徑
+const logindAutoSession = dbus.ObjectPath(logindPath + "/session/auto")
+
+// LogindSession 透過 org.freedesktop.login1 Session 的 LockedHint / IdleHint 回報目前 session 的狀態
+type LogindSession struct {
+	// address 為空字串時使用 system bus
+	address string
+
+	mu   sync.Mutex
+	conn *dbus.Conn
+	path dbus.ObjectPath
+}
+
+// NewLogindSession 建立讀取目前 session 狀態的 LogindSession，連線在第一次查詢時建立
+func NewLogindSession() *LogindSession {
+	return &LogindSession{}
+}
+
+// SessionState 讀取 LockedHint 與 IdleHint
+func (s *LogindSession) SessionState() (SessionState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var state SessionState
+	if err := s.property("LockedHint", &state.Locked); err != nil {
+		return SessionState{}, err
+	}
+	if err := s.property("IdleHint", &state.Idle); err != nil {
+		return SessionState{}, err
+	}
+	return state, nil
+}
+
+// ConsoleTTY 回傳 console session (Type 為 "tty") 的終端機名稱，例如 "tty3"；圖形 session 回傳空字串
+func (s *LogindSession) ConsoleTTY() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var typ, tty string
+	if err := s.property("Type", &typ); err != nil {
+		return "", err
+	}
+	if typ != "tty" {
+		return "", nil
+	}
+	if err := s.property("TTY", &tty); err != nil {
+		return "", err
+	}
+	return tty, nil
+}
+
+// property 讀取 session 的屬性；呼叫失敗時關閉連線，下次查詢重新連線。呼叫端須持有 mu
+func (s *LogindSession) property(name string, dst interface{}) error {
+	if err := s.open(); err != nil {
+		return err
+	}
+	v, err := s.conn.Object(logindDest, s.path).GetProperty(logindDest + ".Session." + name)
+	if err != nil {
+		s.close()
+		return fmt.Errorf("logind Session.%s failed: %w", name, err)
+	}
+	if err := v.Store(dst); err != nil {
+		return fmt.Errorf("logind Session.%s: %w", name, err)
+	}
+	return nil
+}
+
+// Close 關閉 bus 連線
+func (s *LogindSession) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.close()
+	return nil
+}
+
+// open 建立連線並找出本程序所屬的 session；找不到時使用 logind 的 "auto" session
+func (s *LogindSession) open() error {
+	if s.conn != nil {
+		return nil
+	}
+	var conn *dbus.Conn
+	var err error
+	if s.address == "" {
+		conn, err = dbus.ConnectSystemBus()
+	} else {
+		conn, err = dbus.Connect(s.address)
+	}
+	if err != nil {
+		return fmt.Errorf("connect system bus failed: %w", err)
+	}
+	var path dbus.ObjectPath
+	call := conn.Object(logindDest, logindPath).Call(logindDest+".Manager.GetSessionByPID", 0, uint32(os.Getpid()))
+	if err := call.Store(&path); err != nil {
+		path = logindAutoSession
+	}
+	s.conn, s.path = conn, path
+	return nil
+}
+
+func (s *LogindSession) close() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
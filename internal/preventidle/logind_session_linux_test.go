@@ -0,0 +1,59 @@
+//go:build linux
+// +build linux
+
+package preventidle
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/prop"
+)
+
+const stubSessionPath = dbus.ObjectPath(logindPath + "/session/c1")
+
+// stubLogindManager 模擬 Manager.GetSessionByPID
+type stubLogindManager struct{}
+
+func (stubLogindManager) GetSessionByPID(pid uint32) (dbus.ObjectPath, *dbus.Error) {
+	return stubSessionPath, nil
+}
+
+func TestLogindSession_LockedHint(t *testing.T) {
+	bus := startTestBus(t)
+	conn := serveStub(t, bus.address, logindDest, logindPath, logindDest+".Manager", stubLogindManager{})
+	props, err := prop.Export(conn, stubSessionPath, prop.Map{
+		logindDest + ".Session": {
+			"LockedHint": {Value: false, Emit: prop.EmitTrue},
+			"IdleHint":   {Value: false, Emit: prop.EmitTrue},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to export session properties: %v", err)
+	}
+
+	s := NewLogindSession()
+	s.address = bus.address
+	defer s.Close()
+
+	if state, err := s.SessionState(); err != nil || state.Locked || state.Idle {
+		t.Fatalf("Expected unlocked session, got %+v (err=%v)", state, err)
+	}
+
+	props.SetMust(logindDest+".Session", "LockedHint", true)
+	props.SetMust(logindDest+".Session", "IdleHint", true)
+	if state, err := s.SessionState(); err != nil || !state.Locked || !state.Idle {
+		t.Errorf("Expected locked and idle session, got %+v (err=%v)", state, err)
+	}
+}
+
+func TestLogindSession_NoService(t *testing.T) {
+	bus := startTestBus(t)
+
+	s := NewLogindSession()
+	s.address = bus.address
+	defer s.Close()
+	if _, err := s.SessionState(); err == nil {
+		t.Fatal("Expected error when logind is not on the bus, got nil")
+	}
+}
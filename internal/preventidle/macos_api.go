@@ -11,6 +11,11 @@ package preventidle
 #cgo LDFLAGS: -framework IOKit -framework CoreFoundation
 #include <IOKit/pwr_mgt/IOPMLib.h>
 #include <CoreFoundation/CoreFoundation.h>
+
+// CGEventCreateScrollWheelEvent 為 variadic 函式，cgo 無法直接呼叫
+static CGEventRef goidle_scroll_event(CGEventSourceRef source, int32_t lines) {
+	return CGEventCreateScrollWheelEvent(source, kCGScrollEventUnitLine, 1, lines);
+}
 */
 import "C"
 import (
@@ -71,37 +76,48 @@ func AllowIdle() error {
 }
 
 // CallSendInput 使用 Core Graphics API 模擬鍵盤或滑鼠事件。
-// 當 mode 為 "key" 時，以 Shift 鍵 (key code 56) 為示例產生鍵盤按下與釋放事件；
-// 當 mode 為 "mouse" 時，取得目前滑鼠位置，並向右平移 1 像素後建立滑鼠移動事件。
-func CallSendInput(mode string) error {
-	switch mode {
+// 當 in.Type 為 "key" 時，送出 in.Key (含修飾鍵) 的按下與釋放事件；
+// 當 in.Type 為 "mouse" 時，取得目前滑鼠位置，依 in.Mouse 移動後回到原位；
+// "move" / "wheel" 為 sequence 的單次位移與滾動，"scroll" 則滾輪上下各一格。
+func CallSendInput(in Input) error {
+	switch in.Type {
 	case "key":
+		code, mods, err := resolveKey(macKeyCodes, "macOS", in.Key)
+		if err != nil {
+			return err
+		}
 		source := C.CGEventSourceCreate(C.kCGEventSourceStateCombinedSessionState)
 		if source == (C.CGEventSourceRef)(unsafe.Pointer(nil)) {
 			return errors.New("failed to create event source for keyboard")
 		}
 		defer C.CFRelease(C.CFTypeRef(source))
 
-		var key C.CGKeyCode = 56 // 空格鍵 (kVK_Space)
-
-		eventDown := C.CGEventCreateKeyboardEvent(source, key, C.bool(true))
-		if eventDown == (C.CGEventRef)(unsafe.Pointer(nil)) {
-			return errors.New("failed to create key down event")
+		// 修飾鍵的 flags 必須設定在主鍵事件上才會生效
+		var flags uint64
+		for _, m := range mods {
+			flags |= macModifierFlags[m]
+		}
+		keys := append(append([]uint16(nil), mods...), code)
+		for _, k := range keys {
+			if err := postKeyEvent(source, k, true, flags); err != nil {
+				return err
+			}
 		}
-		C.CGEventPost(C.kCGSessionEventTap, eventDown) // 改用 kCGSessionEventTap
-		C.CFRelease(C.CFTypeRef(eventDown))
 
 		C.usleep(10000)
 
-		eventUp := C.CGEventCreateKeyboardEvent(source, key, C.bool(false))
-		if eventUp == (C.CGEventRef)(unsafe.Pointer(nil)) {
-			return errors.New("failed to create key up event")
+		for i := len(keys) - 1; i >= 0; i-- {
+			if err := postKeyEvent(source, keys[i], false, flags); err != nil {
+				return err
+			}
 		}
-		C.CGEventPost(C.kCGSessionEventTap, eventUp)
-		C.CFRelease(C.CFTypeRef(eventUp))
-		logger.LogInfo("macOS: simulated key press (space key)")
+		logger.LogInfof("macOS: simulated key press (%s)", in.Key)
 		return nil
 	case "mouse":
+		path, err := mousePath(in.Mouse)
+		if err != nil {
+			return err
+		}
 		source := C.CGEventSourceCreate(C.kCGEventSourceStateCombinedSessionState)
 		if source == (C.CGEventSourceRef)(unsafe.Pointer(nil)) {
 			return errors.New("failed to create event source for mouse")
@@ -115,34 +131,73 @@ func CallSendInput(mode string) error {
 		location := C.CGEventGetLocation(event)
 		C.CFRelease(C.CFTypeRef(event))
 
-		newX := location.x + 1.0
-		newY := location.y
-		newPoint := C.CGPointMake(newX, newY)
+		// CGEvent 使用絕對座標，最後一點就是起點，不需要點擊也不會漂移
+		for _, p := range path {
+			newPoint := C.CGPointMake(location.x+C.CGFloat(p.x), location.y+C.CGFloat(p.y))
+			mouseEvent := C.CGEventCreateMouseEvent(source, C.kCGEventMouseMoved, newPoint, C.kCGMouseButtonLeft)
+			if mouseEvent == (C.CGEventRef)(unsafe.Pointer(nil)) {
+				return errors.New("failed to create mouse move event")
+			}
+			C.CGEventPost(C.kCGSessionEventTap, mouseEvent)
+			C.CFRelease(C.CFTypeRef(mouseEvent))
+			time.Sleep(mouseStepDelay)
+		}
+
+		logger.LogInfof("macOS: simulated mouse move (%s)", in.Mouse)
+		return nil
+
+	case "move":
+		source := C.CGEventSourceCreate(C.kCGEventSourceStateCombinedSessionState)
+		if source == (C.CGEventSourceRef)(unsafe.Pointer(nil)) {
+			return errors.New("failed to create event source for mouse")
+		}
+		defer C.CFRelease(C.CFTypeRef(source))
+
+		event := C.CGEventCreate(source)
+		if event == (C.CGEventRef)(unsafe.Pointer(nil)) {
+			return errors.New("failed to create event for mouse location")
+		}
+		location := C.CGEventGetLocation(event)
+		C.CFRelease(C.CFTypeRef(event))
 
+		newPoint := C.CGPointMake(location.x+C.CGFloat(in.Dx), location.y+C.CGFloat(in.Dy))
 		mouseEvent := C.CGEventCreateMouseEvent(source, C.kCGEventMouseMoved, newPoint, C.kCGMouseButtonLeft)
 		if mouseEvent == (C.CGEventRef)(unsafe.Pointer(nil)) {
 			return errors.New("failed to create mouse move event")
 		}
 		C.CGEventPost(C.kCGSessionEventTap, mouseEvent)
 		C.CFRelease(C.CFTypeRef(mouseEvent))
+		logger.LogInfof("macOS: simulated mouse move by (%d,%d)", in.Dx, in.Dy)
+		return nil
 
-		clickEvent := C.CGEventCreateMouseEvent(source, C.kCGEventLeftMouseDown, newPoint, C.kCGMouseButtonLeft)
-		if clickEvent == (C.CGEventRef)(unsafe.Pointer(nil)) {
-			return errors.New("failed to create mouse down event")
+	case "wheel":
+		source := C.CGEventSourceCreate(C.kCGEventSourceStateCombinedSessionState)
+		if source == (C.CGEventSourceRef)(unsafe.Pointer(nil)) {
+			return errors.New("failed to create event source for scroll")
 		}
-		C.CGEventPost(C.kCGSessionEventTap, clickEvent)
-		C.CFRelease(C.CFTypeRef(clickEvent))
+		defer C.CFRelease(C.CFTypeRef(source))
 
-		C.usleep(10000)
+		if err := postScrollEvent(source, in.Wheel); err != nil {
+			return err
+		}
+		logger.LogInfof("macOS: simulated wheel scroll (%d)", in.Wheel)
+		return nil
 
-		clickUpEvent := C.CGEventCreateMouseEvent(source, C.kCGEventLeftMouseUp, newPoint, C.kCGMouseButtonLeft)
-		if clickUpEvent == (C.CGEventRef)(unsafe.Pointer(nil)) {
-			return errors.New("failed to create mouse up event")
+	case "scroll":
+		source := C.CGEventSourceCreate(C.kCGEventSourceStateCombinedSessionState)
+		if source == (C.CGEventSourceRef)(unsafe.Pointer(nil)) {
+			return errors.New("failed to create event source for scroll")
 		}
-		C.CGEventPost(C.kCGSessionEventTap, clickUpEvent)
-		C.CFRelease(C.CFTypeRef(clickUpEvent))
+		defer C.CFRelease(C.CFTypeRef(source))
 
-		logger.LogInfo("macOS: simulated mouse move and click")
+		if err := postScrollEvent(source, 1); err != nil {
+			return err
+		}
+		time.Sleep(scrollDelay)
+		if err := postScrollEvent(source, -1); err != nil {
+			return err
+		}
+		logger.LogInfo("macOS: simulated scroll (up/down)")
 		return nil
 
 	default:
@@ -150,6 +205,34 @@ func CallSendInput(mode string) error {
 	}
 }
 
+// postKeyEvent 建立並送出單一按鍵事件
+func postKeyEvent(source C.CGEventSourceRef, key uint16, down bool, flags uint64) error {
+	event := C.CGEventCreateKeyboardEvent(source, C.CGKeyCode(key), C.bool(down))
+	if event == (C.CGEventRef)(unsafe.Pointer(nil)) {
+		if down {
+			return errors.New("failed to create key down event")
+		}
+		return errors.New("failed to create key up event")
+	}
+	if flags != 0 {
+		C.CGEventSetFlags(event, C.CGEventFlags(flags))
+	}
+	C.CGEventPost(C.kCGSessionEventTap, event) // 改用 kCGSessionEventTap
+	C.CFRelease(C.CFTypeRef(event))
+	return nil
+}
+
+// postScrollEvent 送出一次以行為單位的滾輪事件，正數向上
+func postScrollEvent(source C.CGEventSourceRef, lines int) error {
+	event := C.goidle_scroll_event(source, C.int32_t(lines))
+	if event == (C.CGEventRef)(unsafe.Pointer(nil)) {
+		return errors.New("failed to create scroll wheel event")
+	}
+	C.CGEventPost(C.kCGSessionEventTap, event)
+	C.CFRelease(C.CFTypeRef(event))
+	return nil
+}
+
 // GetIdleTime 使用 CGEventSourceSecondsSinceLastEventType 取得系統閒置時間（以秒計），並轉換為 time.Duration。
 func GetIdleTime() (time.Duration, error) {
 	idleSeconds := float64(C.CGEventSourceSecondsSinceLastEventType(C.kCGEventSourceStateCombinedSessionState, C.kCGAnyInputEventType))
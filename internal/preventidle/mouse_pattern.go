@@ -0,0 +1,126 @@
+package preventidle
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// 支援的滑鼠移動模式，所有模式最後都會回到起點
+const (
+	MouseNudge      = "nudge"  // 移動 amplitude 後原路返回
+	MouseSquare     = "square" // 沿邊長 amplitude 的正方形繞一圈
+	MouseCircle     = "circle" // 沿半徑 amplitude 的圓繞一圈
+	MouseRandomWalk = "random" // 在 ±amplitude 的範圍內隨機移動後返回
+)
+
+// DefaultMouse 為未設定時使用的滑鼠移動方式
+var DefaultMouse = Mouse{Pattern: MouseNudge, Amplitude: 1}
+
+const (
+	// randomWalkSteps 為隨機移動的步數 (不含返回起點)
+	randomWalkSteps = 8
+	// mouseStepDelay 為每一步之間的間隔，讓移動速度接近真實滑鼠
+	mouseStepDelay = 2 * time.Millisecond
+	// scrollDelay 為 scroll 動作向上與向下兩次滾動之間的間隔
+	scrollDelay = 20 * time.Millisecond
+)
+
+// point 為相對於起點的位移
+type point struct{ x, y int }
+
+// String 回傳移動模式，例如 "circle(5)"
+func (m Mouse) String() string {
+	m = m.normalized()
+	return fmt.Sprintf("%s(%d)", m.Pattern, m.Amplitude)
+}
+
+func (m Mouse) normalized() Mouse {
+	if m.Pattern == "" {
+		m.Pattern = DefaultMouse.Pattern
+	}
+	m.Pattern = strings.ToLower(m.Pattern)
+	if m.Amplitude <= 0 {
+		m.Amplitude = DefaultMouse.Amplitude
+	}
+	return m
+}
+
+// mousePath 回傳模式經過的位移點 (相對於起點)，最後一點一定是 {0, 0}
+func mousePath(m Mouse) ([]point, error) {
+	m = m.normalized()
+	a := m.Amplitude
+	switch m.Pattern {
+	case MouseNudge:
+		return []point{{a, 0}, {0, 0}}, nil
+
+	case MouseSquare:
+		return []point{{a, 0}, {a, a}, {0, a}, {0, 0}}, nil
+
+	case MouseCircle:
+		// 圓心在起點左方 a 處，從起點出發繞一圈
+		n := 8 * a
+		if n < 8 {
+			n = 8
+		}
+		path := make([]point, 0, n)
+		for i := 1; i < n; i++ {
+			theta := 2 * math.Pi * float64(i) / float64(n)
+			path = append(path, point{
+				x: int(math.Round(float64(a)*math.Cos(theta))) - a,
+				y: int(math.Round(float64(a) * math.Sin(theta))),
+			})
+		}
+		return append(path, point{0, 0}), nil
+
+	case MouseRandomWalk:
+		path := make([]point, 0, randomWalkSteps+1)
+		var cur point
+		for i := 0; i < randomWalkSteps; i++ {
+			cur.x = clamp(cur.x+rand.IntN(3)-1, -a, a)
+			cur.y = clamp(cur.y+rand.IntN(3)-1, -a, a)
+			path = append(path, cur)
+		}
+		return append(path, point{0, 0}), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported mouse pattern %q", m.Pattern)
+	}
+}
+
+// mouseSteps 將模式拆成每軸最多移動 1 單位的相對位移。
+// 相同大小的正負位移經過指標加速後仍會互相抵銷，因此只靠相對移動的 backend 也能回到起點。
+func mouseSteps(m Mouse) ([]point, error) {
+	path, err := mousePath(m)
+	if err != nil {
+		return nil, err
+	}
+	var steps []point
+	var cur point
+	for _, p := range path {
+		for cur != p {
+			step := point{sign(p.x - cur.x), sign(p.y - cur.y)}
+			cur.x += step.x
+			cur.y += step.y
+			steps = append(steps, step)
+		}
+	}
+	return steps, nil
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func clamp(v, lo, hi int) int {
+	return min(max(v, lo), hi)
+}
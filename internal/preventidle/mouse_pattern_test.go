@@ -0,0 +1,61 @@
+package preventidle
+
+import "testing"
+
+func TestMouseSteps_ReturnToOrigin(t *testing.T) {
+	for _, pattern := range []string{MouseNudge, MouseSquare, MouseCircle, MouseRandomWalk} {
+		for _, amplitude := range []int{0, 1, 5, 20} {
+			m := Mouse{Pattern: pattern, Amplitude: amplitude}
+			steps, err := mouseSteps(m)
+			if err != nil {
+				t.Fatalf("mouseSteps(%s) failed: %v", m, err)
+			}
+			if len(steps) == 0 {
+				t.Errorf("Expected %s to move the cursor", m)
+			}
+
+			var cur point
+			limit := max(amplitude, 1)
+			for _, s := range steps {
+				if s.x < -1 || s.x > 1 || s.y < -1 || s.y > 1 {
+					t.Fatalf("Expected unit steps for %s, got %+v", m, s)
+				}
+				cur.x += s.x
+				cur.y += s.y
+				if abs(cur.x) > 2*limit || abs(cur.y) > 2*limit {
+					t.Fatalf("Expected %s to stay near the origin, reached %+v", m, cur)
+				}
+			}
+			if cur != (point{}) {
+				t.Errorf("Expected %s to end at the origin, ended at %+v", m, cur)
+			}
+		}
+	}
+}
+
+func TestMousePath_RandomWalkBounded(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		path, err := mousePath(Mouse{Pattern: MouseRandomWalk, Amplitude: 2})
+		if err != nil {
+			t.Fatalf("mousePath failed: %v", err)
+		}
+		for _, p := range path {
+			if abs(p.x) > 2 || abs(p.y) > 2 {
+				t.Fatalf("Expected random walk to stay within ±2, got %+v", p)
+			}
+		}
+	}
+}
+
+func TestMousePath_Unsupported(t *testing.T) {
+	if _, err := mousePath(Mouse{Pattern: "zigzag"}); err == nil {
+		t.Error("Expected error for unsupported pattern, got nil")
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
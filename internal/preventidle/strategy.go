@@ -0,0 +1,100 @@
+package preventidle
+
+import (
+	"fmt"
+	"sync"
+)
+
+// InhibitStrategy 代表以主 backend 的電源宣告防止閒置的策略名稱
+const InhibitStrategy = "inhibit"
+
+// DefaultMaxFailures 為策略連續失敗幾次後降級的預設值
+const DefaultMaxFailures = 3
+
+// IsInhibit 回傳此策略是否以電源宣告 (而非模擬輸入) 防止閒置
+func (s *Strategy) IsInhibit() bool {
+	return s.Input == nil
+}
+
+// OpenStrategies 依名稱建立 fallback chain 的策略：
+// "inhibit" 使用 base 的電源宣告，其餘名稱為 backend 名稱，有輸入元件時模擬輸入，否則使用其電源宣告。
+// names 為空時依 mode 由 base 建立單一策略，行為與未設定 strategies 時相同。
+func OpenStrategies(names []string, base *Backend, mode string) ([]*Strategy, error) {
+	if len(names) == 0 {
+		if mode == "inhibit" {
+			names = []string{InhibitStrategy}
+		} else {
+			return []*Strategy{{Name: base.Name, Input: base.Input}}, nil
+		}
+	}
+
+	strategies := make([]*Strategy, 0, len(names))
+	for _, name := range names {
+		if name == InhibitStrategy {
+			if base.Inhibitor == nil {
+				return nil, fmt.Errorf("strategy %q: backend %s does not support power assertions", name, base.Name)
+			}
+			strategies = append(strategies, &Strategy{Name: name, Inhibitor: base.Inhibitor})
+			continue
+		}
+		b, err := NewBackend(name)
+		if err != nil {
+			return nil, fmt.Errorf("strategy %q: %w", name, err)
+		}
+		switch {
+		case b.Input != nil:
+			strategies = append(strategies, &Strategy{Name: name, Input: b.Input})
+		case b.Inhibitor != nil:
+			strategies = append(strategies, &Strategy{Name: name, Inhibitor: b.Inhibitor})
+		default:
+			return nil, fmt.Errorf("strategy %q: backend provides neither input nor power assertions", name)
+		}
+	}
+	return strategies, nil
+}
+
+// StrategyChain 依優先順序保存策略，目前的策略連續失敗 maxFailures 次後降級到下一個，
+// 最後一個也失敗時回到第一個重新嘗試。
+type StrategyChain struct {
+	mu          sync.Mutex
+	strategies  []*Strategy
+	maxFailures int
+	active      int
+	failures    int
+}
+
+// NewStrategyChain 建立 fallback chain，maxFailures <= 0 時使用 DefaultMaxFailures
+func NewStrategyChain(strategies []*Strategy, maxFailures int) *StrategyChain {
+	if maxFailures <= 0 {
+		maxFailures = DefaultMaxFailures
+	}
+	return &StrategyChain{strategies: strategies, maxFailures: maxFailures}
+}
+
+// Active 回傳目前使用中的策略
+func (c *StrategyChain) Active() *Strategy {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.strategies[c.active]
+}
+
+// Succeeded 重設目前策略的連續失敗次數
+func (c *StrategyChain) Succeeded() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures = 0
+}
+
+// Failed 記錄目前策略失敗一次；達到 maxFailures 時降級並回傳降級前的策略，否則回傳 nil
+func (c *StrategyChain) Failed() (demoted *Strategy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures++
+	if c.failures < c.maxFailures {
+		return nil
+	}
+	demoted = c.strategies[c.active]
+	c.active = (c.active + 1) % len(c.strategies)
+	c.failures = 0
+	return demoted
+}
@@ -0,0 +1,62 @@
+package preventidle
+
+import "testing"
+
+func TestOpenStrategies_Default(t *testing.T) {
+	fake := NewFakeBackend()
+
+	strategies, err := OpenStrategies(nil, fake.Backend(), "mouse")
+	if err != nil {
+		t.Fatalf("OpenStrategies failed: %v", err)
+	}
+	if len(strategies) != 1 || strategies[0].Name != FakeBackendName || strategies[0].IsInhibit() {
+		t.Errorf("Expected a single input strategy from the base backend, got %+v", strategies)
+	}
+
+	strategies, err = OpenStrategies(nil, fake.Backend(), "inhibit")
+	if err != nil {
+		t.Fatalf("OpenStrategies failed: %v", err)
+	}
+	if len(strategies) != 1 || !strategies[0].IsInhibit() {
+		t.Errorf("Expected a single inhibit strategy in inhibit mode, got %+v", strategies)
+	}
+}
+
+func TestOpenStrategies_Named(t *testing.T) {
+	strategies, err := OpenStrategies([]string{InhibitStrategy, FakeBackendName}, NewFakeBackend().Backend(), "key")
+	if err != nil {
+		t.Fatalf("OpenStrategies failed: %v", err)
+	}
+	if len(strategies) != 2 || !strategies[0].IsInhibit() || strategies[1].IsInhibit() {
+		t.Errorf("Expected [inhibit fake] strategies, got %+v", strategies)
+	}
+
+	if _, err := OpenStrategies([]string{"no-such-backend"}, NewFakeBackend().Backend(), "key"); err == nil {
+		t.Error("Expected error for unknown strategy, got nil")
+	}
+}
+
+func TestStrategyChain_Demotion(t *testing.T) {
+	chain := NewStrategyChain([]*Strategy{{Name: "a"}, {Name: "b"}}, 2)
+
+	if demoted := chain.Failed(); demoted != nil {
+		t.Fatalf("Expected no demotion after one failure, got %s", demoted.Name)
+	}
+	chain.Succeeded()
+	if demoted := chain.Failed(); demoted != nil {
+		t.Fatalf("Expected success to reset the failure count, got demotion of %s", demoted.Name)
+	}
+	if demoted := chain.Failed(); demoted == nil || demoted.Name != "a" {
+		t.Fatalf("Expected a to be demoted, got %v", demoted)
+	}
+	if got := chain.Active().Name; got != "b" {
+		t.Fatalf("Expected b to be active, got %s", got)
+	}
+
+	// 最後一個策略也失敗時回到第一個
+	chain.Failed()
+	chain.Failed()
+	if got := chain.Active().Name; got != "a" {
+		t.Errorf("Expected chain to wrap around to a, got %s", got)
+	}
+}
@@ -1,5 +1,7 @@
 package preventidle
 
+import "time"
+
 type IdleController struct {
 	StopChan chan struct{}
 	Running  bool
@@ -32,6 +34,12 @@ type MouseInput struct {
 	dwExtraInfo uintptr
 }
 
+// point32 對應 Windows 的 POINT
+type point32 struct {
+	X int32
+	Y int32
+}
+
 type CallKeyboardInput struct {
 	Type uint32
 	_    [4]byte       // padding
@@ -44,3 +52,105 @@ type CallMouseInput struct {
 	_    [4]byte    // padding
 	Mi   MouseInput // KEYBDINPUT
 }
+
+// UinputPermissionError 表示目前使用者沒有 /dev/uinput 的寫入權限
+type UinputPermissionError struct {
+	Path string
+	Err  error
+}
+
+// UserActiveError 表示送出模擬輸入前偵測到使用者剛有真正的輸入，剩餘的步驟已取消
+type UserActiveError struct {
+	Idle  time.Duration // 使用者真正的閒置時間
+	Input Input         // 被取消的輸入
+}
+
+// IdleSource 回報使用者已閒置多久
+type IdleSource interface {
+	IdleTime() (time.Duration, error)
+}
+
+// InputInjector 送出一次模擬輸入
+type InputInjector interface {
+	SendInput(in Input) error
+}
+
+// Key 為可攜式按鍵名稱 (例如 "F15"、"Shift"、"ScrollLock") 與同時按住的修飾鍵，由各平台轉換成實際的 key code
+type Key struct {
+	Name      string
+	Modifiers []string
+}
+
+// Mouse 為滑鼠移動模式 (MouseNudge 等) 與幅度 (像素或裝置單位)
+type Mouse struct {
+	Pattern   string
+	Amplitude int
+}
+
+// Input 描述一次模擬輸入
+type Input struct {
+	Type   string // "key"、"mouse"、"scroll" (滾輪上下各一格)、"move" (相對移動 Dx / Dy，不返回) 或 "wheel" (滾動 Wheel 格)
+	Key    Key    // Type 為 "key" 時送出的按鍵，零值為 DefaultKey
+	Mouse  Mouse  // Type 為 "mouse" 時的移動模式，零值為 DefaultMouse
+	Dx, Dy int    // Type 為 "move" 時的位移
+	Wheel  int    // Type 為 "wheel" 時的滾輪格數，正數向上、負數向下
+}
+
+// Step 為 sequence 模式的一個步驟
+type Step struct {
+	Action string        // "key"、"move"、"scroll"、"wait"
+	Key    Key           // key
+	Dx, Dy int           // move
+	Amount int           // scroll
+	Wait   time.Duration // wait
+}
+
+// SimulateOptions 為 SimulateActivityWith 的輸入內容設定
+type SimulateOptions struct {
+	Key      Key    // key / mixed 模式送出的按鍵
+	Mouse    Mouse  // mouse / mixed 模式的移動模式
+	Sequence []Step // sequence 模式依序執行的步驟
+	// Cancel 關閉時中斷 sequence 的 wait 步驟並取消剩餘步驟，nil 表示不可中斷
+	Cancel <-chan struct{}
+}
+
+// DryRunRecord 為 dry-run backend 記錄的一次「原本會執行」的動作
+type DryRunRecord struct {
+	Time   time.Time
+	Action string // 例如 "key ctrl+f15"、"mouse circle(5)"、"inhibit"、"release"
+}
+
+// SessionState 為目前登入 session 的狀態
+type SessionState struct {
+	Locked bool // 螢幕已鎖定 (logind LockedHint)
+	Idle   bool // session 已被判定為閒置 (logind IdleHint)
+}
+
+// SessionStateSource 回報目前 session 的狀態
+type SessionStateSource interface {
+	SessionState() (SessionState, error)
+}
+
+// PowerInhibitor 持有 / 釋放防止閒置的電源宣告
+type PowerInhibitor interface {
+	Inhibit() error
+	Release() error
+}
+
+// Backend 組合一組防閒置元件，不支援的元件為 nil
+type Backend struct {
+	Name      string
+	Idle      IdleSource
+	Input     InputInjector
+	Inhibitor PowerInhibitor
+}
+
+// BackendFactory 建立一個具名 backend
+type BackendFactory func() (*Backend, error)
+
+// Strategy 為 fallback chain 中的一種防閒置方式，Input 與 Inhibitor 只會有一個非 nil
+type Strategy struct {
+	Name      string
+	Input     InputInjector
+	Inhibitor PowerInhibitor
+}
@@ -0,0 +1,259 @@
+//go:build linux
+// +build linux
+
+package preventidle
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/HanksJCTsai/goidleguard/pkg/logger"
+)
+
+// uinput ioctl 代碼 (linux/uinput.h，以通用的 _IO/_IOW 編碼計算)
+const (
+	uiDevCreate = 0x5501     // _IO('U', 1)
+	uiDevSetup  = 0x405c5503 // _IOW('U', 3, struct uinput_setup)
+	uiSetEvBit  = 0x40045564 // _IOW('U', 100, int)
+	uiSetKeyBit = 0x40045565 // _IOW('U', 101, int)
+	uiSetRelBit = 0x40045566 // _IOW('U', 102, int)
+
+	evSyn = 0x00
+	evKey = 0x01
+	evRel = 0x02
+
+	synReport = 0
+	relX      = 0x00
+	relY      = 0x01
+	relWheel  = 0x08
+
+	btnLeft = 0x110
+
+	busVirtual = 0x06
+)
+
+// uinputPath 為虛擬輸入裝置的控制節點
+var uinputPath = "/dev/uinput"
+
+// uinputSetup 對應 struct uinput_setup
+type uinputSetup struct {
+	BusType      uint16
+	Vendor       uint16
+	Product      uint16
+	Version      uint16
+	Name         [80]byte
+	FFEffectsMax uint32
+}
+
+// inputEvent 對應 struct input_event
+type inputEvent struct {
+	Time  syscall.Timeval
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+var (
+	uinputMu     sync.Mutex
+	uinputDevice *os.File
+)
+
+// newUinputOpenError 依 errno 將開啟 /dev/uinput 的錯誤轉為可辨識的型別
+func newUinputOpenError(path string, err error) error {
+	if errors.Is(err, syscall.EACCES) || errors.Is(err, syscall.EPERM) {
+		return &UinputPermissionError{Path: path, Err: err}
+	}
+	if errors.Is(err, syscall.ENOENT) {
+		return fmt.Errorf("%s not found (is the uinput kernel module loaded?): %w", path, err)
+	}
+	return fmt.Errorf("open %s failed: %w", path, err)
+}
+
+func uinputIoctl(f *os.File, req, arg uintptr) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), req, arg); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// uinputOpen 建立 (或重用) 一個同時具備鍵盤與相對座標滑鼠能力的虛擬裝置。
+// 呼叫端必須持有 uinputMu。
+func uinputOpen() (*os.File, error) {
+	if uinputDevice != nil {
+		return uinputDevice, nil
+	}
+
+	f, err := os.OpenFile(uinputPath, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, newUinputOpenError(uinputPath, err)
+	}
+
+	steps := []struct {
+		req, arg uintptr
+	}{
+		{uiSetEvBit, evKey},
+		{uiSetEvBit, evRel},
+		// 必須宣告至少一個滑鼠按鍵，libinput 才會把裝置視為指標裝置
+		{uiSetKeyBit, btnLeft},
+		{uiSetRelBit, relX},
+		{uiSetRelBit, relY},
+		{uiSetRelBit, relWheel},
+	}
+	// 宣告所有可設定的按鍵，裝置建立後就不能再新增
+	for _, code := range uinputKeyCodes {
+		steps = append(steps, struct{ req, arg uintptr }{uiSetKeyBit, uintptr(code)})
+	}
+	for _, s := range steps {
+		if err := uinputIoctl(f, s.req, s.arg); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("uinput ioctl 0x%x failed: %w", s.req, err)
+		}
+	}
+
+	setup := uinputSetup{BusType: busVirtual, Vendor: 0x1d6b, Product: 0x0104, Version: 1}
+	copy(setup.Name[:], "GoIdleGuard virtual input")
+	if err := uinputIoctl(f, uiDevSetup, uintptr(unsafe.Pointer(&setup))); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("UI_DEV_SETUP failed: %w", err)
+	}
+	if err := uinputIoctl(f, uiDevCreate, 0); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("UI_DEV_CREATE failed: %w", err)
+	}
+
+	// 給 udev 與 compositor 時間辨識新裝置，否則最初的事件可能會遺失
+	time.Sleep(200 * time.Millisecond)
+
+	uinputDevice = f
+	logger.LogInfo("Linux/uinput: virtual input device created")
+	return uinputDevice, nil
+}
+
+// uinputEmit 寫入一組事件並以 SYN_REPORT 結尾
+func uinputEmit(f *os.File, events ...inputEvent) error {
+	events = append(events, inputEvent{Type: evSyn, Code: synReport})
+	for i := range events {
+		buf := (*[unsafe.Sizeof(inputEvent{})]byte)(unsafe.Pointer(&events[i]))[:]
+		if _, err := f.Write(buf); err != nil {
+			return fmt.Errorf("write uinput event failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// uinputSendInput 透過 /dev/uinput 虛擬裝置模擬鍵盤或滑鼠事件，適用於沒有 XTest 的 Wayland 與 console session。
+// 當 in.Type 為 "key" 時，送出 in.Key (含修飾鍵) 的按下與釋放；當 in.Type 為 "mouse" 時，依 in.Mouse 移動後回到起點；
+// "move" / "wheel" 為 sequence 的單次位移與滾動，"scroll" 則滾輪上下各一格。
+func uinputSendInput(in Input) error {
+	uinputMu.Lock()
+	defer uinputMu.Unlock()
+
+	switch in.Type {
+	case "key":
+		code, mods, err := resolveKey(uinputKeyCodes, "Linux/uinput", in.Key)
+		if err != nil {
+			return err
+		}
+		f, err := uinputOpen()
+		if err != nil {
+			return err
+		}
+		if err := uinputKeyCombo(f, code, mods); err != nil {
+			return err
+		}
+		logger.LogInfof("Linux/uinput: simulated key press (%s)", in.Key)
+		return nil
+
+	case "mouse":
+		steps, err := mouseSteps(in.Mouse)
+		if err != nil {
+			return err
+		}
+		f, err := uinputOpen()
+		if err != nil {
+			return err
+		}
+		for _, st := range steps {
+			var events []inputEvent
+			if st.x != 0 {
+				events = append(events, inputEvent{Type: evRel, Code: relX, Value: int32(st.x)})
+			}
+			if st.y != 0 {
+				events = append(events, inputEvent{Type: evRel, Code: relY, Value: int32(st.y)})
+			}
+			if err := uinputEmit(f, events...); err != nil {
+				return err
+			}
+			time.Sleep(mouseStepDelay)
+		}
+		logger.LogInfof("Linux/uinput: simulated mouse move (%s)", in.Mouse)
+		return nil
+
+	case "move":
+		f, err := uinputOpen()
+		if err != nil {
+			return err
+		}
+		if err := uinputEmit(f,
+			inputEvent{Type: evRel, Code: relX, Value: int32(in.Dx)},
+			inputEvent{Type: evRel, Code: relY, Value: int32(in.Dy)}); err != nil {
+			return err
+		}
+		logger.LogInfof("Linux/uinput: simulated mouse move by (%d,%d)", in.Dx, in.Dy)
+		return nil
+
+	case "wheel":
+		f, err := uinputOpen()
+		if err != nil {
+			return err
+		}
+		if err := uinputEmit(f, inputEvent{Type: evRel, Code: relWheel, Value: int32(in.Wheel)}); err != nil {
+			return err
+		}
+		logger.LogInfof("Linux/uinput: simulated wheel scroll (%d)", in.Wheel)
+		return nil
+
+	case "scroll":
+		f, err := uinputOpen()
+		if err != nil {
+			return err
+		}
+		if err := uinputEmit(f, inputEvent{Type: evRel, Code: relWheel, Value: 1}); err != nil {
+			return err
+		}
+		time.Sleep(scrollDelay)
+		if err := uinputEmit(f, inputEvent{Type: evRel, Code: relWheel, Value: -1}); err != nil {
+			return err
+		}
+		logger.LogInfo("Linux/uinput: simulated scroll (up/down)")
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported mode for CallSendInput on Linux/uinput: %s", in.Type)
+	}
+}
+
+// uinputKeyCombo 依序按下修飾鍵與主鍵，再以相反順序放開；即使中途失敗也會嘗試放開已按下的鍵
+func uinputKeyCombo(f *os.File, code uint16, mods []uint16) error {
+	keys := append(append([]uint16(nil), mods...), code)
+	pressed := 0
+	var err error
+	for _, k := range keys {
+		if err = uinputEmit(f, inputEvent{Type: evKey, Code: k, Value: 1}); err != nil {
+			break
+		}
+		pressed++
+	}
+	time.Sleep(10 * time.Millisecond)
+	for i := pressed - 1; i >= 0; i-- {
+		if rerr := uinputEmit(f, inputEvent{Type: evKey, Code: keys[i], Value: 0}); err == nil {
+			err = rerr
+		}
+	}
+	return err
+}
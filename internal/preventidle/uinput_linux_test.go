@@ -0,0 +1,53 @@
+//go:build linux
+// +build linux
+
+package preventidle
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestNewUinputOpenError_Permission(t *testing.T) {
+	for _, errno := range []syscall.Errno{syscall.EACCES, syscall.EPERM} {
+		err := newUinputOpenError("/dev/uinput", &os.PathError{Op: "open", Path: "/dev/uinput", Err: errno})
+
+		var permErr *UinputPermissionError
+		if !errors.As(err, &permErr) {
+			t.Fatalf("Expected UinputPermissionError for %v, got %T: %v", errno, err, err)
+		}
+		if permErr.Path != "/dev/uinput" {
+			t.Errorf("Expected path /dev/uinput, got %s", permErr.Path)
+		}
+		if !errors.Is(err, errno) {
+			t.Errorf("Expected error to wrap %v", errno)
+		}
+	}
+}
+
+func TestUinputSendInput_MissingDevice(t *testing.T) {
+	old := uinputPath
+	uinputPath = filepath.Join(t.TempDir(), "uinput")
+	defer func() { uinputPath = old }()
+
+	err := uinputSendInput(Input{Type: "key"})
+	if err == nil {
+		t.Fatal("Expected error when uinput device is missing, got nil")
+	}
+	var permErr *UinputPermissionError
+	if errors.As(err, &permErr) {
+		t.Errorf("Expected a not-found error, got permission error: %v", err)
+	}
+	if !errors.Is(err, syscall.ENOENT) {
+		t.Errorf("Expected error to wrap ENOENT, got %v", err)
+	}
+}
+
+func TestUinputSendInput_UnsupportedKey(t *testing.T) {
+	if err := uinputSendInput(Input{Type: "key", Key: Key{Name: "hyper"}}); err == nil {
+		t.Error("Expected error for unsupported key, got nil")
+	}
+}
@@ -5,6 +5,8 @@ package preventidle
 
 import (
 	"fmt"
+	"runtime"
+	"sync"
 	"syscall"
 	"time"
 	"unsafe"
@@ -20,6 +22,8 @@ var (
 	user32               = syscall.NewLazyDLL("user32.dll")
 	procGetLastInputInfo = user32.NewProc("GetLastInputInfo")
 	procSendInput        = user32.NewProc("SendInput")
+	procGetCursorPos     = user32.NewProc("GetCursorPos")
+	procSetCursorPos     = user32.NewProc("SetCursorPos")
 )
 
 const (
@@ -33,15 +37,56 @@ const (
 	// keyboard event flags
 	KEYEVENTF_KEYUP = 0x0002
 
-	// virtual key codes
-	VK_SPACE = 0x20
+	// mouse event flags
+	MOUSEEVENTF_MOVE  = 0x0001
+	MOUSEEVENTF_WHEEL = 0x0800
+
+	// 滾輪一格的單位
+	WHEEL_DELTA = 120
+
+	// injectedExtraInfo 標記在我們送出的每個事件的 dwExtraInfo 上 ("GIDG")，
+	// 讓 low-level hook 或其他工具能分辨 GoIdleGuard 的模擬輸入與使用者的輸入
+	injectedExtraInfo = 0x47494447
+)
+
+var (
+	esOnce     sync.Once
+	esRequests chan esRequest
 )
 
+type esRequest struct {
+	flags  uintptr
+	result chan error
+}
+
+// setExecutionState 呼叫 SetThreadExecutionState。
+// 該設定綁定在呼叫的 OS 執行緒上，而 goroutine 可能在執行緒間切換，
+// 因此所有呼叫都交由同一個鎖定執行緒的 goroutine 執行，確保 AllowIdle 能清除 PreventSleep 的設定。
+func setExecutionState(flags uintptr) error {
+	esOnce.Do(func() {
+		esRequests = make(chan esRequest)
+		go func() {
+			runtime.LockOSThread()
+			for req := range esRequests {
+				ret, _, err := procSetThreadExecutionState.Call(req.flags)
+				if ret == 0 {
+					req.result <- err
+					continue
+				}
+				req.result <- nil
+			}
+		}()
+	})
+
+	result := make(chan error, 1)
+	esRequests <- esRequest{flags: flags, result: result}
+	return <-result
+}
+
 // PreventSleep 建立「PreventUserIdleSystemSleep」宣告
 func PreventSleep() error {
 	flags := ES_CONTINUOUS | ES_SYSTEM_REQUIRED | ES_DISPLAY_REQUIRED
-	ret, _, err := procSetThreadExecutionState.Call(uintptr(flags))
-	if ret == 0 {
+	if err := setExecutionState(uintptr(flags)); err != nil {
 		return fmt.Errorf("SetThreadExecutionState failed: %v", err)
 	}
 	logger.LogInfo("Windows: PreventSleep asserted")
@@ -50,83 +95,159 @@ func PreventSleep() error {
 
 // AllowIdle 恢復系統與顯示器閒置行為
 func AllowIdle() error {
-	ret, _, err := procSetThreadExecutionState.Call(uintptr(ES_CONTINUOUS))
-	if ret == 0 {
+	if err := setExecutionState(uintptr(ES_CONTINUOUS)); err != nil {
 		return fmt.Errorf("SetThreadExecutionState clear failed: %v", err)
 	}
 	logger.LogInfo("Windows: AllowSleep restored")
 	return nil
 }
 
-// CallSendInput 使用 Core Graphics API 模擬鍵盤或滑鼠事件。
-// 當 mode 為 "key" 時，以 Shift 鍵 (key code 56) 為示例產生鍵盤按下與釋放事件；
-// 當 mode 為 "mouse" 時，取得目前滑鼠位置，並向右平移 1 像素後建立滑鼠移動事件
-func CallSendInput(mode string) error {
-	switch mode {
+// CallSendInput 使用 SendInput API 模擬鍵盤或滑鼠事件。
+// 當 in.Type 為 "key" 時，送出 in.Key (含修飾鍵) 的按下與釋放事件；
+// 當 in.Type 為 "mouse" 時，依 in.Mouse 送出相對移動事件並回到起點；
+// "move" / "wheel" 為 sequence 的單次位移與滾動，"scroll" 則滾輪上下各一格
+func CallSendInput(in Input) error {
+	switch in.Type {
 	case "key":
-		// key down
-		ki := CallKeyboardInput{
-			Type: INPUT_KEYBOARD,
-			Ki: KeyboardInput{
-				WVk:         VK_SPACE,
-				WScan:       0,
-				DwFlags:     0,
-				Time:        0,
-				DwExtraInfo: 0,
-			},
+		vk, mods, err := resolveKey(vkCodes, "Windows", in.Key)
+		if err != nil {
+			return err
 		}
-		n, _, err := procSendInput.Call(1, uintptr(unsafe.Pointer(&ki)), unsafe.Sizeof(ki))
-		if n == 0 {
-			return fmt.Errorf("SendInput key down failed: %v", err)
+		if err := sendKeyCombo(vk, mods); err != nil {
+			return err
 		}
-		// key up
-		ki.Ki.DwFlags = KEYEVENTF_KEYUP
-		n, _, err = procSendInput.Call(1, uintptr(unsafe.Pointer(&ki)), unsafe.Sizeof(ki))
-		if n == 0 {
-			return fmt.Errorf("SendInput key up failed: %v", err)
-		}
-		logger.LogInfo("Windows: simulated key press (space)")
+		logger.LogInfof("Windows: simulated key press (%s)", in.Key)
 		return nil
 
 	case "mouse":
-		// get current cursor position and move by +1 X
-		// here we simply send relative move
-		mi := CallMouseInput{
-			Type: INPUT_MOUSE,
-			Mi: MouseInput{
-				dx:          10,
-				dy:          0,
-				dwFlags:     0x0001, // MOUSEEVENTF_MOVE
-				dwExtraInfo: 0,
-			},
+		steps, err := mouseSteps(in.Mouse)
+		if err != nil {
+			return err
+		}
+		// 記下起點，移動結束後以 SetCursorPos 校正，避免指標加速造成的誤差累積成漂移
+		var start point32
+		hasStart := false
+		if ret, _, _ := procGetCursorPos.Call(uintptr(unsafe.Pointer(&start))); ret != 0 {
+			hasStart = true
 		}
-		n, _, err := procSendInput.Call(1, uintptr(unsafe.Pointer(&mi)), unsafe.Sizeof(mi))
-		if n == 0 {
-			return fmt.Errorf("SendInput mouse move failed: %v", err)
+		for _, st := range steps {
+			if err := sendMouseMove(int32(st.x), int32(st.y)); err != nil {
+				return err
+			}
+			time.Sleep(mouseStepDelay)
 		}
+		if hasStart {
+			procSetCursorPos.Call(uintptr(start.X), uintptr(start.Y))
+		}
+		logger.LogInfof("Windows: simulated mouse move (%s)", in.Mouse)
+		return nil
 
-		mi = CallMouseInput{
-			Type: INPUT_MOUSE,
-			Mi: MouseInput{
-				dx:          10,
-				dy:          0,
-				dwFlags:     0x0001, // MOUSEEVENTF_MOVE
-				dwExtraInfo: 0,
-			},
+	case "move":
+		if err := sendMouseMove(int32(in.Dx), int32(in.Dy)); err != nil {
+			return err
 		}
-		n, _, err = procSendInput.Call(1, uintptr(unsafe.Pointer(&mi)), unsafe.Sizeof(mi))
-		if n == 0 {
-			return fmt.Errorf("SendInput mouse move failed: %v", err)
+		logger.LogInfof("Windows: simulated mouse move by (%d,%d)", in.Dx, in.Dy)
+		return nil
+
+	case "wheel":
+		if err := sendMouseWheel(int32(in.Wheel) * WHEEL_DELTA); err != nil {
+			return err
 		}
+		logger.LogInfof("Windows: simulated wheel scroll (%d)", in.Wheel)
+		return nil
 
-		logger.LogInfo("Windows: simulated mouse move")
+	case "scroll":
+		if err := sendMouseWheel(WHEEL_DELTA); err != nil {
+			return err
+		}
+		time.Sleep(scrollDelay)
+		if err := sendMouseWheel(-WHEEL_DELTA); err != nil {
+			return err
+		}
+		logger.LogInfo("Windows: simulated scroll (up/down)")
 		return nil
 
 	default:
-		return fmt.Errorf("unsupported mode for CallSendInput on Windows: %s", mode)
+		return fmt.Errorf("unsupported mode for CallSendInput on Windows: %s", in.Type)
 	}
 }
 
+// sendKeyCombo 依序按下修飾鍵與主鍵，再以相反順序放開；即使中途失敗也會嘗試放開已按下的鍵
+func sendKeyCombo(vk uint16, mods []uint16) error {
+	keys := append(append([]uint16(nil), mods...), vk)
+	pressed := 0
+	var err error
+	for _, k := range keys {
+		if err = sendKey(k, 0); err != nil {
+			break
+		}
+		pressed++
+	}
+	for i := pressed - 1; i >= 0; i-- {
+		if rerr := sendKey(keys[i], KEYEVENTF_KEYUP); err == nil {
+			err = rerr
+		}
+	}
+	return err
+}
+
+// sendKey 送出單一按鍵事件
+func sendKey(vk uint16, flags uint32) error {
+	ki := CallKeyboardInput{
+		Type: INPUT_KEYBOARD,
+		Ki: KeyboardInput{
+			WVk:         vk,
+			WScan:       0,
+			DwFlags:     flags,
+			Time:        0,
+			DwExtraInfo: injectedExtraInfo,
+		},
+	}
+	n, _, err := procSendInput.Call(1, uintptr(unsafe.Pointer(&ki)), unsafe.Sizeof(ki))
+	if n == 0 {
+		if flags&KEYEVENTF_KEYUP != 0 {
+			return fmt.Errorf("SendInput key up failed: %v", err)
+		}
+		return fmt.Errorf("SendInput key down failed: %v", err)
+	}
+	return nil
+}
+
+// sendMouseMove 送出一次相對移動事件
+func sendMouseMove(dx, dy int32) error {
+	mi := CallMouseInput{
+		Type: INPUT_MOUSE,
+		Mi: MouseInput{
+			dx:          dx,
+			dy:          dy,
+			dwFlags:     MOUSEEVENTF_MOVE,
+			dwExtraInfo: injectedExtraInfo,
+		},
+	}
+	n, _, err := procSendInput.Call(1, uintptr(unsafe.Pointer(&mi)), unsafe.Sizeof(mi))
+	if n == 0 {
+		return fmt.Errorf("SendInput mouse move failed: %v", err)
+	}
+	return nil
+}
+
+// sendMouseWheel 送出一次滾輪事件，delta 為 WHEEL_DELTA 的倍數，正數向上
+func sendMouseWheel(delta int32) error {
+	mi := CallMouseInput{
+		Type: INPUT_MOUSE,
+		Mi: MouseInput{
+			mouseData:   uint32(delta),
+			dwFlags:     MOUSEEVENTF_WHEEL,
+			dwExtraInfo: injectedExtraInfo,
+		},
+	}
+	n, _, err := procSendInput.Call(1, uintptr(unsafe.Pointer(&mi)), unsafe.Sizeof(mi))
+	if n == 0 {
+		return fmt.Errorf("SendInput mouse wheel failed: %v", err)
+	}
+	return nil
+}
+
 // GetIdleTime 使用 CGEventSourceSecondsSinceLastEventType 取得系統閒置時間（以秒計），並轉換為 time.Duration。
 func GetIdleTime() (time.Duration, error) {
 	// 先取得 LASTINPUTINFO
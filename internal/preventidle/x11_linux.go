@@ -0,0 +1,365 @@
+//go:build linux
+// +build linux
+
+package preventidle
+
+/*
+#cgo LDFLAGS: -ldl
+#include <dlfcn.h>
+#include <stdlib.h>
+
+// 僅宣告本檔案用到的 Xlib 型別，讓編譯時不需要 X11 開發標頭檔；
+// 實際的函式庫於執行期以 dlopen 載入 (類似 Windows 的 LazyDLL)。
+typedef struct _XDisplay Display;
+typedef unsigned long XID;
+typedef XID Window;
+typedef unsigned long KeySym;
+typedef unsigned char KeyCode;
+typedef int Bool;
+
+typedef struct {
+	Window window;
+	int state;
+	int kind;
+	unsigned long til_or_since;
+	unsigned long idle;
+	unsigned long eventMask;
+} XScreenSaverInfo;
+
+typedef struct {
+	int type;
+	Display *display;
+	XID resourceid;
+	unsigned long serial;
+	unsigned char error_code;
+	unsigned char request_code;
+	unsigned char minor_code;
+} XErrorEvent;
+
+typedef int (*XErrorHandler)(Display *, XErrorEvent *);
+
+static Display *(*pXOpenDisplay)(const char *);
+static Window (*pXDefaultRootWindow)(Display *);
+static int (*pXFlush)(Display *);
+static int (*pXSync)(Display *, Bool);
+static int (*pXFree)(void *);
+static KeyCode (*pXKeysymToKeycode)(Display *, KeySym);
+static XErrorHandler (*pXSetErrorHandler)(XErrorHandler);
+
+static Bool (*pXScreenSaverQueryExtension)(Display *, int *, int *);
+static XScreenSaverInfo *(*pXScreenSaverAllocInfo)(void);
+static int (*pXScreenSaverQueryInfo)(Display *, Window, XScreenSaverInfo *);
+static void (*pXScreenSaverSuspend)(Display *, Bool);
+
+static Bool (*pXTestQueryExtension)(Display *, int *, int *, int *, int *);
+static int (*pXTestFakeKeyEvent)(Display *, unsigned int, Bool, unsigned long);
+static int (*pXTestFakeRelativeMotionEvent)(Display *, int, int, unsigned long);
+static int (*pXTestFakeButtonEvent)(Display *, unsigned int, Bool, unsigned long);
+
+#define GOIDLE_SYM(lib, name) \
+	if ((*(void **)(&p##name) = dlsym(lib, #name)) == NULL) return #name;
+
+// goidle_x11_load 載入 libX11 / libXss / libXtst，失敗時回傳缺少的函式庫或符號名稱
+static const char *goidle_x11_load(void) {
+	void *x11 = dlopen("libX11.so.6", RTLD_NOW | RTLD_GLOBAL);
+	if (x11 == NULL) return "libX11.so.6";
+	void *xss = dlopen("libXss.so.1", RTLD_NOW);
+	if (xss == NULL) return "libXss.so.1";
+	void *xtst = dlopen("libXtst.so.6", RTLD_NOW);
+	if (xtst == NULL) return "libXtst.so.6";
+
+	GOIDLE_SYM(x11, XOpenDisplay)
+	GOIDLE_SYM(x11, XDefaultRootWindow)
+	GOIDLE_SYM(x11, XFlush)
+	GOIDLE_SYM(x11, XSync)
+	GOIDLE_SYM(x11, XFree)
+	GOIDLE_SYM(x11, XKeysymToKeycode)
+	GOIDLE_SYM(x11, XSetErrorHandler)
+	GOIDLE_SYM(xss, XScreenSaverQueryExtension)
+	GOIDLE_SYM(xss, XScreenSaverAllocInfo)
+	GOIDLE_SYM(xss, XScreenSaverQueryInfo)
+	GOIDLE_SYM(xss, XScreenSaverSuspend)
+	GOIDLE_SYM(xtst, XTestQueryExtension)
+	GOIDLE_SYM(xtst, XTestFakeKeyEvent)
+	GOIDLE_SYM(xtst, XTestFakeRelativeMotionEvent)
+	GOIDLE_SYM(xtst, XTestFakeButtonEvent)
+	return NULL;
+}
+
+// Xlib 預設的錯誤處理會直接結束行程，改為只記錄錯誤碼
+static int goidle_x_error_code;
+
+static int goidle_x_error_handler(Display *dpy, XErrorEvent *ev) {
+	goidle_x_error_code = ev->error_code;
+	return 0;
+}
+
+static Display *goidle_x11_open(void) {
+	Display *dpy = pXOpenDisplay(NULL);
+	if (dpy != NULL) {
+		pXSetErrorHandler(goidle_x_error_handler);
+	}
+	return dpy;
+}
+
+static int goidle_has_xss(Display *dpy) {
+	int ev, err;
+	return pXScreenSaverQueryExtension(dpy, &ev, &err);
+}
+
+static int goidle_has_xtest(Display *dpy) {
+	int ev, err, major, minor;
+	return pXTestQueryExtension(dpy, &ev, &err, &major, &minor);
+}
+
+static int goidle_idle_ms(Display *dpy, unsigned long *idle) {
+	XScreenSaverInfo *info = pXScreenSaverAllocInfo();
+	if (info == NULL) return 0;
+	int ok = pXScreenSaverQueryInfo(dpy, pXDefaultRootWindow(dpy), info);
+	*idle = info->idle;
+	pXFree(info);
+	return ok;
+}
+
+static int goidle_fake_key(Display *dpy, KeySym sym, Bool press) {
+	KeyCode code = pXKeysymToKeycode(dpy, sym);
+	if (code == 0) return 0;
+	goidle_x_error_code = 0;
+	pXTestFakeKeyEvent(dpy, code, press, 0);
+	pXSync(dpy, 0);
+	return goidle_x_error_code == 0;
+}
+
+static int goidle_fake_motion(Display *dpy, int dx, int dy) {
+	goidle_x_error_code = 0;
+	pXTestFakeRelativeMotionEvent(dpy, dx, dy, 0);
+	pXSync(dpy, 0);
+	return goidle_x_error_code == 0;
+}
+
+// goidle_fake_click 按下並放開指定的滑鼠按鍵；X11 的滾輪為 button 4 (上) / 5 (下)
+static int goidle_fake_click(Display *dpy, unsigned int button) {
+	goidle_x_error_code = 0;
+	pXTestFakeButtonEvent(dpy, button, 1, 0);
+	pXTestFakeButtonEvent(dpy, button, 0, 0);
+	pXSync(dpy, 0);
+	return goidle_x_error_code == 0;
+}
+
+static void goidle_suspend(Display *dpy, Bool suspend) {
+	pXScreenSaverSuspend(dpy, suspend);
+	pXFlush(dpy);
+}
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/HanksJCTsai/goidleguard/pkg/logger"
+)
+
+// ErrDisplayUnavailable 表示無法使用 X11 display (未設定 DISPLAY、缺少 Xlib 函式庫或連線失敗)
+var ErrDisplayUnavailable = errors.New("X11 display unavailable")
+
+var (
+	x11Once    sync.Once
+	x11LoadErr error
+
+	// Xlib 並非執行緒安全，所有對 x11Display 的存取都必須持有 x11Mu
+	x11Mu      sync.Mutex
+	x11Display *C.Display
+	hasXss     bool
+	hasXTest   bool
+)
+
+// x11Connect 取得共用的 X11 連線，第一次呼叫時載入函式庫並檢查 XScreenSaver / XTest 擴充。
+// 呼叫端必須持有 x11Mu。
+func x11Connect() (*C.Display, error) {
+	x11Once.Do(func() {
+		if missing := C.goidle_x11_load(); missing != nil {
+			x11LoadErr = fmt.Errorf("%w: cannot load %s", ErrDisplayUnavailable, C.GoString(missing))
+		}
+	})
+	if x11LoadErr != nil {
+		return nil, x11LoadErr
+	}
+	if x11Display != nil {
+		return x11Display, nil
+	}
+	if os.Getenv("DISPLAY") == "" {
+		return nil, fmt.Errorf("%w: DISPLAY is not set", ErrDisplayUnavailable)
+	}
+
+	dpy := C.goidle_x11_open()
+	if dpy == nil {
+		return nil, fmt.Errorf("%w: cannot open display %q", ErrDisplayUnavailable, os.Getenv("DISPLAY"))
+	}
+	x11Display = dpy
+	hasXss = C.goidle_has_xss(dpy) != 0
+	hasXTest = C.goidle_has_xtest(dpy) != 0
+	logger.LogInfof("Linux/X11: connected to %s (XScreenSaver=%v, XTest=%v)", os.Getenv("DISPLAY"), hasXss, hasXTest)
+	return x11Display, nil
+}
+
+// x11PreventSleep 透過 XScreenSaverSuspend 暫停螢幕保護程式與 DPMS，直到 x11AllowIdle 被呼叫
+func x11PreventSleep() error {
+	x11Mu.Lock()
+	defer x11Mu.Unlock()
+
+	dpy, err := x11Connect()
+	if err != nil {
+		return err
+	}
+	if !hasXss {
+		return errors.New("X server does not support the MIT-SCREEN-SAVER extension")
+	}
+	C.goidle_suspend(dpy, 1)
+	logger.LogInfo("Linux/X11: screensaver suspended")
+	return nil
+}
+
+// x11AllowIdle 恢復螢幕保護程式與 DPMS
+func x11AllowIdle() error {
+	x11Mu.Lock()
+	defer x11Mu.Unlock()
+
+	dpy, err := x11Connect()
+	if err != nil {
+		return err
+	}
+	if !hasXss {
+		return errors.New("X server does not support the MIT-SCREEN-SAVER extension")
+	}
+	C.goidle_suspend(dpy, 0)
+	logger.LogInfo("Linux/X11: screensaver resumed")
+	return nil
+}
+
+// x11SendInput 使用 XTest 擴充模擬鍵盤或滑鼠事件。
+// 當 in.Type 為 "key" 時，送出 in.Key (含修飾鍵) 的按下與釋放事件；
+// 當 in.Type 為 "mouse" 時，依 in.Mouse 移動游標後回到原位；
+// "move" / "wheel" 為 sequence 的單次位移與滾動，"scroll" 則滾輪上下各一格。
+func x11SendInput(in Input) error {
+	x11Mu.Lock()
+	defer x11Mu.Unlock()
+
+	dpy, err := x11Connect()
+	if err != nil {
+		return err
+	}
+	if !hasXTest {
+		return errors.New("X server does not support the XTEST extension")
+	}
+
+	switch in.Type {
+	case "key":
+		sym, mods, err := resolveKey(x11Keysyms, "Linux/X11", in.Key)
+		if err != nil {
+			return err
+		}
+		if err := x11KeyCombo(dpy, sym, mods); err != nil {
+			return err
+		}
+		logger.LogInfof("Linux/X11: simulated key press (%s)", in.Key)
+		return nil
+
+	case "mouse":
+		steps, err := mouseSteps(in.Mouse)
+		if err != nil {
+			return err
+		}
+		for _, st := range steps {
+			if C.goidle_fake_motion(dpy, C.int(st.x), C.int(st.y)) == 0 {
+				return errors.New("XTestFakeRelativeMotionEvent failed")
+			}
+			time.Sleep(mouseStepDelay)
+		}
+		logger.LogInfof("Linux/X11: simulated mouse move (%s)", in.Mouse)
+		return nil
+
+	case "move":
+		if C.goidle_fake_motion(dpy, C.int(in.Dx), C.int(in.Dy)) == 0 {
+			return errors.New("XTestFakeRelativeMotionEvent failed")
+		}
+		logger.LogInfof("Linux/X11: simulated mouse move by (%d,%d)", in.Dx, in.Dy)
+		return nil
+
+	case "wheel":
+		if err := x11Wheel(dpy, in.Wheel); err != nil {
+			return err
+		}
+		logger.LogInfof("Linux/X11: simulated wheel scroll (%d)", in.Wheel)
+		return nil
+
+	case "scroll":
+		if err := x11Wheel(dpy, 1); err != nil {
+			return err
+		}
+		time.Sleep(scrollDelay)
+		if err := x11Wheel(dpy, -1); err != nil {
+			return err
+		}
+		logger.LogInfo("Linux/X11: simulated scroll (up/down)")
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported mode for CallSendInput on Linux/X11: %s", in.Type)
+	}
+}
+
+// x11KeyCombo 依序按下修飾鍵與主鍵，再以相反順序放開；即使中途失敗也會嘗試放開已按下的鍵
+func x11KeyCombo(dpy *C.Display, sym uint32, mods []uint32) error {
+	keys := append(append([]uint32(nil), mods...), sym)
+	pressed := 0
+	var err error
+	for _, k := range keys {
+		if C.goidle_fake_key(dpy, C.KeySym(k), 1) == 0 {
+			err = fmt.Errorf("XTestFakeKeyEvent failed for keysym 0x%x", k)
+			break
+		}
+		pressed++
+	}
+	time.Sleep(10 * time.Millisecond)
+	for i := pressed - 1; i >= 0; i-- {
+		C.goidle_fake_key(dpy, C.KeySym(keys[i]), 0)
+	}
+	return err
+}
+
+// x11Wheel 以 button 4 / 5 的點擊模擬滾輪，每格一次
+func x11Wheel(dpy *C.Display, amount int) error {
+	button := C.uint(4)
+	if amount < 0 {
+		button, amount = 5, -amount
+	}
+	for i := 0; i < amount; i++ {
+		if C.goidle_fake_click(dpy, button) == 0 {
+			return errors.New("XTestFakeButtonEvent failed")
+		}
+	}
+	return nil
+}
+
+// x11GetIdleTime 使用 XScreenSaverQueryInfo 取得 X server 記錄的使用者閒置時間
+func x11GetIdleTime() (time.Duration, error) {
+	x11Mu.Lock()
+	defer x11Mu.Unlock()
+
+	dpy, err := x11Connect()
+	if err != nil {
+		return 0, err
+	}
+	if !hasXss {
+		return 0, errors.New("X server does not support the MIT-SCREEN-SAVER extension")
+	}
+
+	var idleMs C.ulong
+	if C.goidle_idle_ms(dpy, &idleMs) == 0 {
+		return 0, errors.New("XScreenSaverQueryInfo failed")
+	}
+	return time.Duration(idleMs) * time.Millisecond, nil
+}
@@ -0,0 +1,83 @@
+//go:build linux
+// +build linux
+
+package preventidle
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// 需要可用的 X server，例如：
+//
+//	Xvfb :99 & DISPLAY=:99 go test ./internal/preventidle/
+func requireX11(t *testing.T) {
+	t.Helper()
+	if os.Getenv("DISPLAY") == "" {
+		t.Skip("DISPLAY is not set; start Xvfb to run X11 tests")
+	}
+	if _, err := x11GetIdleTime(); errors.Is(err, ErrDisplayUnavailable) {
+		t.Skipf("X11 unavailable: %v", err)
+	}
+}
+
+func TestX11_GetIdleTime(t *testing.T) {
+	requireX11(t)
+
+	idle, err := x11GetIdleTime()
+	if err != nil {
+		t.Fatalf("GetIdleTime failed: %v", err)
+	}
+	if idle < 0 {
+		t.Errorf("Expected non-negative idle time, got %v", idle)
+	}
+}
+
+func TestX11_CallSendInputResetsIdle(t *testing.T) {
+	requireX11(t)
+
+	for _, mode := range []string{"key", "mouse", "scroll"} {
+		time.Sleep(300 * time.Millisecond)
+		if err := x11SendInput(Input{Type: mode}); err != nil {
+			t.Fatalf("x11SendInput(%q) failed: %v", mode, err)
+		}
+		idle, err := x11GetIdleTime()
+		if err != nil {
+			t.Fatalf("GetIdleTime failed: %v", err)
+		}
+		if idle >= 300*time.Millisecond {
+			t.Errorf("Expected idle time to be reset by %q input, got %v", mode, idle)
+		}
+	}
+}
+
+func TestX11_PreventSleepAndAllowIdle(t *testing.T) {
+	requireX11(t)
+
+	if err := x11PreventSleep(); err != nil {
+		t.Fatalf("PreventSleep failed: %v", err)
+	}
+	if err := x11AllowIdle(); err != nil {
+		t.Fatalf("AllowIdle failed: %v", err)
+	}
+}
+
+func TestX11_UnsupportedMode(t *testing.T) {
+	requireX11(t)
+
+	if err := x11SendInput(Input{Type: "invalid"}); err == nil {
+		t.Error("Expected error for unsupported mode, got nil")
+	}
+}
+
+func TestX11_XTestAlias(t *testing.T) {
+	strategies, err := OpenStrategies([]string{InhibitStrategy, "uinput", "xtest"}, NewFakeBackend().Backend(), "key")
+	if err != nil {
+		t.Fatalf("OpenStrategies failed: %v", err)
+	}
+	if got := strategies[2]; got.Name != "xtest" || got.Input == nil {
+		t.Errorf("Expected xtest to open the XTest input path, got %+v", got)
+	}
+}
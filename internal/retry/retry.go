@@ -0,0 +1,81 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/HanksJCTsai/goidleguard/internal/config"
+)
+
+// ErrStopped 表示等待重試期間收到停止訊號
+var ErrStopped = errors.New("retry stopped")
+
+// NewPolicy 由設定建立 Policy；設定已由 ValidateConfig 驗證，無法解析的間隔視為 0
+func NewPolicy(cfg config.RetryPolicyConfig) Policy {
+	interval, _ := time.ParseDuration(cfg.RetryInterval)
+	var maxInterval time.Duration
+	if cfg.MaxInterval != "" {
+		maxInterval, _ = time.ParseDuration(cfg.MaxInterval)
+	}
+	return Policy{
+		MaxRetries:  cfg.MaxRetries,
+		Interval:    interval,
+		Backoff:     cfg.Backoff,
+		MaxInterval: maxInterval,
+		Jitter:      cfg.Jitter,
+	}
+}
+
+// Delay 回傳第 retry 次重試 (從 1 開始) 前要等待的時間
+func (p Policy) Delay(retry int) time.Duration {
+	d := p.Interval
+	if p.Backoff {
+		for i := 1; i < retry; i++ {
+			d *= 2
+			if p.MaxInterval > 0 && d >= p.MaxInterval {
+				d = p.MaxInterval
+				break
+			}
+		}
+	}
+	if p.Jitter > 0 && d > 0 {
+		// 在 [d*(1-jitter), d*(1+jitter)] 之間隨機取值
+		d += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(d))
+	}
+	return d
+}
+
+// Do 執行 fn，失敗時依 Policy 重試。每次失敗都會呼叫 onError (可為 nil)；
+// 重試用盡時回傳 *ExhaustedError，stop 被關閉時回傳 ErrStopped。
+func (p Policy) Do(op string, stop <-chan struct{}, fn func() error, onError func(attempt int, err error)) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if onError != nil {
+			onError(attempt, err)
+		}
+		if attempt > p.MaxRetries {
+			return &ExhaustedError{Op: op, Attempts: attempt, Err: err}
+		}
+
+		timer := time.NewTimer(p.Delay(attempt))
+		select {
+		case <-stop:
+			timer.Stop()
+			return ErrStopped
+		case <-timer.C:
+		}
+	}
+}
+
+func (e *ExhaustedError) Error() string {
+	return fmt.Sprintf("%s failed after %d attempts: %v", e.Op, e.Attempts, e.Err)
+}
+
+func (e *ExhaustedError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,79 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPolicyDo_SucceedsAfterRetry(t *testing.T) {
+	p := Policy{MaxRetries: 2, Interval: time.Millisecond}
+
+	calls := 0
+	var attempts []int
+	err := p.Do("op", nil, func() error {
+		calls++
+		if calls < 2 {
+			return errors.New("transient")
+		}
+		return nil
+	}, func(attempt int, err error) {
+		attempts = append(attempts, attempt)
+	})
+	if err != nil {
+		t.Fatalf("Expected success after retry, got %v", err)
+	}
+	if calls != 2 || len(attempts) != 1 || attempts[0] != 1 {
+		t.Errorf("Expected 2 calls and 1 reported failure, got calls=%d attempts=%v", calls, attempts)
+	}
+}
+
+func TestPolicyDo_Exhausted(t *testing.T) {
+	p := Policy{MaxRetries: 2, Interval: time.Millisecond}
+	cause := errors.New("permanent")
+
+	calls := 0
+	err := p.Do("GetIdleTime", nil, func() error {
+		calls++
+		return cause
+	}, nil)
+
+	var exhausted *ExhaustedError
+	if !errors.As(err, &exhausted) {
+		t.Fatalf("Expected ExhaustedError, got %v", err)
+	}
+	if calls != 3 || exhausted.Attempts != 3 || exhausted.Op != "GetIdleTime" {
+		t.Errorf("Expected 3 attempts, got calls=%d err=%+v", calls, exhausted)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("Expected ExhaustedError to wrap the last error")
+	}
+}
+
+func TestPolicyDo_Stopped(t *testing.T) {
+	p := Policy{MaxRetries: 5, Interval: time.Hour}
+	stop := make(chan struct{})
+	close(stop)
+
+	err := p.Do("op", stop, func() error { return errors.New("fail") }, nil)
+	if !errors.Is(err, ErrStopped) {
+		t.Errorf("Expected ErrStopped, got %v", err)
+	}
+}
+
+func TestPolicyDelay(t *testing.T) {
+	p := Policy{Interval: time.Second, Backoff: true, MaxInterval: 5 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := p.Delay(i + 1); got != w {
+			t.Errorf("Delay(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+
+	p = Policy{Interval: time.Second, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		if got := p.Delay(1); got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("Delay with jitter out of range: %v", got)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package retry
+
+import "time"
+
+// Policy 描述失敗時的重試方式
+type Policy struct {
+	MaxRetries  int           // 第一次失敗後最多再嘗試幾次
+	Interval    time.Duration // 第一次重試前的等待時間
+	Backoff     bool          // 每次重試後等待時間加倍
+	MaxInterval time.Duration // 指數退避的等待上限，0 表示不限制
+	Jitter      float64       // 等待時間的隨機變動比例 (0~1)
+}
+
+// ExhaustedError 表示重試次數用盡仍然失敗
+type ExhaustedError struct {
+	Op       string
+	Attempts int
+	Err      error
+}
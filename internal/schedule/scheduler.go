@@ -11,6 +11,7 @@ func InitialScheduler(cfg *config.APPConfig) *Scheduler {
 	return &Scheduler{
 		Config:   cfg,
 		StopChan: make(chan struct{}),
+		wake:     make(chan time.Duration, 1),
 	}
 }
 
@@ -43,6 +44,10 @@ func (s *Scheduler) ScheduleTask(task func()) {
 
 		ticker := time.NewTicker(s.Config.Scheduler.Interval)
 		defer ticker.Stop()
+		// wakeTimer 為 WakeAfter 排定的額外執行，與週期執行在同一個 goroutine 中依序呼叫 task
+		wakeTimer := time.NewTimer(time.Hour)
+		wakeTimer.Stop()
+		defer wakeTimer.Stop()
 
 		for {
 			select {
@@ -50,11 +55,32 @@ func (s *Scheduler) ScheduleTask(task func()) {
 				return
 			case <-ticker.C:
 				task()
+			case d := <-s.wake:
+				wakeTimer.Reset(d)
+			case <-wakeTimer.C:
+				task()
 			}
 		}
 	}()
 }
 
+// WakeAfter 要求在 d 之後額外執行一次 task (不影響原本的週期)，取代尚未執行的前一個要求；
+// 排程尚未啟動時會在啟動後生效
+func (s *Scheduler) WakeAfter(d time.Duration) {
+	for {
+		select {
+		case s.wake <- d:
+			return
+		default:
+		}
+		// 丟棄尚未處理的舊要求
+		select {
+		case <-s.wake:
+		default:
+		}
+	}
+}
+
 func (s *Scheduler) StopScheduler() {
 	close(s.StopChan)
 	s.WG.Wait()
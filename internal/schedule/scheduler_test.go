@@ -57,6 +57,7 @@ func TestCheckWorkTime(t *testing.T) {
 func TestSchedulerScheduleTask(t *testing.T) {
 	// 測試 ScheduleTask 是否正確啟動 task
 	cfg := &config.APPConfig{
+		Scheduler: config.SchedulerConfig{Interval: 10 * time.Millisecond},
 		WorkSchedule: config.WorkSchedule{
 			"monday": {
 				{Start: "00:00", End: "00:01"}, // 測試用，設定為幾乎不影響
@@ -80,3 +81,30 @@ func TestSchedulerScheduleTask(t *testing.T) {
 	}
 	s.StopScheduler()
 }
+
+func TestSchedulerWakeAfter(t *testing.T) {
+	// 週期很長，task 只會因 WakeAfter 而執行
+	cfg := &config.APPConfig{Scheduler: config.SchedulerConfig{Interval: time.Hour}}
+	s := InitialScheduler(cfg)
+	ran := make(chan time.Time, 2)
+	s.ScheduleTask(func() { ran <- time.Now() })
+	defer s.StopScheduler()
+
+	// 後一個要求取代前一個
+	start := time.Now()
+	s.WakeAfter(time.Hour)
+	s.WakeAfter(30 * time.Millisecond)
+	select {
+	case at := <-ran:
+		if d := at.Sub(start); d < 30*time.Millisecond {
+			t.Errorf("Expected the task to run after 30ms, ran after %v", d)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected WakeAfter to run the task")
+	}
+	select {
+	case <-ran:
+		t.Error("Expected the replaced wake-up not to run")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,51 @@
+package schedule
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/HanksJCTsai/goidleguard/internal/config"
+)
+
+// NewTiming 由設定建立 Timing，model 為空字串時使用 fixed
+func NewTiming(cfg config.TimingConfig) *Timing {
+	model := cfg.Model
+	if model == "" {
+		model = "fixed"
+	}
+	return &Timing{
+		Model:  model,
+		Jitter: cfg.Jitter,
+		Mean:   cfg.Mean,
+		MinGap: cfg.MinGap,
+		MaxGap: cfg.MaxGap,
+		rand:   rand.Float64,
+	}
+}
+
+// Delay 回傳達到閒置門檻後要額外等待的時間
+func (t *Timing) Delay() time.Duration {
+	switch t.Model {
+	case "uniform":
+		return time.Duration(t.rand() * float64(t.Jitter))
+	case "poisson":
+		// Poisson 過程的到達間隔為指數分布
+		return time.Duration(-math.Log(1-t.rand()) * float64(t.Mean))
+	default:
+		return 0
+	}
+}
+
+// NextFire 回傳下一次送出模擬輸入的時間：lastInput 為最後一次輸入 (使用者或模擬) 的時間，
+// 間隔為 idle 門檻加上 Delay()，並限制在 [MinGap, MaxGap] 之間。
+func (t *Timing) NextFire(lastInput time.Time, threshold time.Duration) time.Time {
+	gap := threshold + t.Delay()
+	if t.MinGap > 0 && gap < t.MinGap {
+		gap = t.MinGap
+	}
+	if t.MaxGap > 0 && gap > t.MaxGap {
+		gap = t.MaxGap
+	}
+	return lastInput.Add(gap)
+}
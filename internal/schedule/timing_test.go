@@ -0,0 +1,43 @@
+package schedule
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/HanksJCTsai/goidleguard/internal/config"
+)
+
+func TestTimingNextFire(t *testing.T) {
+	last := time.Date(2025, time.April, 7, 9, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name string
+		cfg  config.TimingConfig
+		r    float64
+		want time.Duration
+	}{
+		{"fixed", config.TimingConfig{}, 0.7, time.Minute},
+		{"uniform", config.TimingConfig{Model: "uniform", Jitter: 20 * time.Second}, 0.5, time.Minute + 10*time.Second},
+		{"poisson", config.TimingConfig{Model: "poisson", Mean: 10 * time.Second}, 1 - 1/math.E, time.Minute + 10*time.Second},
+		{"min gap", config.TimingConfig{MinGap: 90 * time.Second}, 0, 90 * time.Second},
+		{"max gap", config.TimingConfig{Model: "uniform", Jitter: time.Hour, MaxGap: 2 * time.Minute}, 0.9, 2 * time.Minute},
+	}
+	for _, tt := range tests {
+		timing := NewTiming(tt.cfg)
+		timing.rand = func() float64 { return tt.r }
+		got := timing.NextFire(last, time.Minute).Sub(last)
+		if diff := got - tt.want; diff < -time.Millisecond || diff > time.Millisecond {
+			t.Errorf("%s: expected gap %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestTimingDelay_UniformRange(t *testing.T) {
+	timing := NewTiming(config.TimingConfig{Model: "uniform", Jitter: time.Second})
+	for i := 0; i < 100; i++ {
+		if d := timing.Delay(); d < 0 || d >= time.Second {
+			t.Fatalf("Expected uniform delay in [0, 1s), got %v", d)
+		}
+	}
+}
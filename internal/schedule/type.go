@@ -2,6 +2,7 @@ package schedule
 
 import (
 	"sync"
+	"time"
 
 	"github.com/HanksJCTsai/goidleguard/internal/config"
 )
@@ -10,4 +11,17 @@ type Scheduler struct {
 	Config   *config.APPConfig
 	StopChan chan struct{}
 	WG       sync.WaitGroup
+	// wake 接收 WakeAfter 要求的額外執行時間，只保留最新的一個
+	wake chan time.Duration
+}
+
+// Timing 決定達到閒置門檻後還要再等多久才送出模擬輸入
+type Timing struct {
+	Model  string
+	Jitter time.Duration
+	Mean   time.Duration
+	MinGap time.Duration
+	MaxGap time.Duration
+	// rand 回傳 [0, 1) 的亂數，測試時可替換
+	rand func() float64
 }
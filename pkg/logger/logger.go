@@ -22,12 +22,19 @@ func InitLogger() {
 	errorLogger = log.New(os.Stderr, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile)
 }
 
+// ensureLogger 第一次使用時初始化；若 logger 已被 InitLogger 或測試設定過則保留
+func ensureLogger() {
+	loggerOnce.Do(func() {
+		if infoLogger == nil || debugLogger == nil || errorLogger == nil {
+			InitLogger()
+		}
+	})
+}
+
 // SetOutput 設定輸出目標
 func SetOutput(w io.Writer) {
 	// 1. 先確保已經初始化 (避免 nil pointer)
-	loggerOnce.Do(func() {
-		InitLogger()
-	})
+	ensureLogger()
 
 	// 2. 強制更改輸出目標為檔案
 	// 重要：這會修改現有的 logger 實例，而不是建立新的
@@ -44,18 +51,36 @@ func SetOutput(w io.Writer) {
 
 // LogInfo ...
 func LogInfo(v ...interface{}) {
-	loggerOnce.Do(func() { InitLogger() })
+	ensureLogger()
 	infoLogger.Println(v...)
 }
 
 // LogError ...
 func LogError(v ...interface{}) {
-	loggerOnce.Do(func() { InitLogger() })
+	ensureLogger()
 	errorLogger.Println(v...)
 }
 
 // LogDebug ...
 func LogDebug(v ...interface{}) {
-	loggerOnce.Do(func() { InitLogger() })
+	ensureLogger()
 	debugLogger.Println(v...)
 }
+
+// LogInfof 以 fmt.Printf 格式記錄 Info 級別日誌
+func LogInfof(format string, v ...interface{}) {
+	ensureLogger()
+	infoLogger.Printf(format, v...)
+}
+
+// LogErrorf 以 fmt.Printf 格式記錄 Error 級別日誌
+func LogErrorf(format string, v ...interface{}) {
+	ensureLogger()
+	errorLogger.Printf(format, v...)
+}
+
+// LogDebugf 以 fmt.Printf 格式記錄 Debug 級別日誌
+func LogDebugf(format string, v ...interface{}) {
+	ensureLogger()
+	debugLogger.Printf(format, v...)
+}
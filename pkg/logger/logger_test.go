@@ -31,6 +31,22 @@ func TestLogFunctions(t *testing.T) {
 	}
 }
 
+func TestLogFormattedFunctions(t *testing.T) {
+	var buf bytes.Buffer
+	infoLogger = log.New(&buf, "INFO: ", 0)
+	debugLogger = log.New(&buf, "DEBUG: ", 0)
+	errorLogger = log.New(&buf, "ERROR: ", 0)
+
+	LogInfof("idle=%v/%v", 3, 5)
+	LogDebugf("mode=%s", "key")
+	LogErrorf("failed: %d", 42)
+
+	output := buf.String()
+	if !contains(output, "idle=3/5") || !contains(output, "mode=key") || !contains(output, "failed: 42") {
+		t.Errorf("Formatted log functions did not produce expected output: %s", output)
+	}
+}
+
 // contains 是檢查子字串是否存在的輔助函式
 func contains(s, substr string) bool {
 	return bytes.Contains([]byte(s), []byte(substr))